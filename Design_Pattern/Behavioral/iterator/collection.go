@@ -0,0 +1,148 @@
+package iterator
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrConcurrentModification is returned by a fail-fast iterator when its
+// collection was modified after the iterator was created.
+var ErrConcurrentModification = errors.New("iterator: collection modified during iteration")
+
+// Collection is a slice backed collection that is safe for concurrent use.
+// It hands out snapshot iterators, which keep a stable view while writers
+// carry on, and fail-fast iterators, which stop as soon as a writer changes
+// the collection.
+type Collection[T any] struct {
+	mu      sync.RWMutex
+	items   []T
+	version uint64
+}
+
+// NewCollection returns a collection holding items.
+func NewCollection[T any](items ...T) *Collection[T] {
+	return &Collection[T]{items: append([]T(nil), items...)}
+}
+
+// Add appends items to the collection.
+func (c *Collection[T]) Add(items ...T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = append(c.items, items...)
+	c.version++
+}
+
+// Set replaces the item at index i.
+func (c *Collection[T]) Set(i int, item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[i] = item
+	c.version++
+}
+
+// Remove deletes the item at index i.
+func (c *Collection[T]) Remove(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = append(c.items[:i:i], c.items[i+1:]...)
+	c.version++
+}
+
+// Get returns the item at index i.
+func (c *Collection[T]) Get(i int) T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.items[i]
+}
+
+// Len returns the number of items in the collection.
+func (c *Collection[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// Snapshot returns an iterator over a copy of the collection as it is now.
+// Later writes are not visible to it.
+func (c *Collection[T]) Snapshot() *SliceIterator[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return NewSliceIterator(append([]T(nil), c.items...))
+}
+
+// FailFast returns an iterator that reads the live collection and fails
+// with ErrConcurrentModification once the collection has been modified.
+func (c *Collection[T]) FailFast() *FailFastIterator[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &FailFastIterator[T]{c: c, version: c.version}
+}
+
+// SliceIterator iterates over a slice it owns.
+type SliceIterator[T any] struct {
+	items []T
+	index int
+	cur   T
+}
+
+// NewSliceIterator returns an iterator over items. The slice must not be
+// modified while it is being iterated.
+func NewSliceIterator[T any](items []T) *SliceIterator[T] {
+	return &SliceIterator[T]{items: items}
+}
+
+func (s *SliceIterator[T]) Next() bool {
+	var zero T
+	s.cur = zero
+	if s.index >= len(s.items) {
+		return false
+	}
+	s.cur = s.items[s.index]
+	s.index++
+	return true
+}
+
+func (s *SliceIterator[T]) Value() T {
+	return s.cur
+}
+
+func (s *SliceIterator[T]) Err() error {
+	return nil
+}
+
+// FailFastIterator iterates over a live Collection.
+type FailFastIterator[T any] struct {
+	c       *Collection[T]
+	version uint64
+	index   int
+	cur     T
+	err     error
+}
+
+func (f *FailFastIterator[T]) Next() bool {
+	var zero T
+	f.cur = zero
+	if f.err != nil {
+		return false
+	}
+	f.c.mu.RLock()
+	defer f.c.mu.RUnlock()
+	if f.c.version != f.version {
+		f.err = ErrConcurrentModification
+		return false
+	}
+	if f.index >= len(f.c.items) {
+		return false
+	}
+	f.cur = f.c.items[f.index]
+	f.index++
+	return true
+}
+
+func (f *FailFastIterator[T]) Value() T {
+	return f.cur
+}
+
+func (f *FailFastIterator[T]) Err() error {
+	return f.err
+}
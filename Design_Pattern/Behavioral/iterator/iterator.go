@@ -0,0 +1,21 @@
+// Package iterator contains iterators for collections that do not fit in a
+// plain slice, such as paginated APIs and streamed files.
+package iterator
+
+// Iterator walks a sequence of values one at a time. Next advances to the
+// next value and reports whether there is one, Value returns the current
+// value and Err reports the error, if any, that stopped the iteration.
+type Iterator[T any] interface {
+	Next() bool
+	Value() T
+	Err() error
+}
+
+// Collect drains it into a slice.
+func Collect[T any](it Iterator[T]) ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,156 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestOffsetIterator(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7}
+	var calls int
+	it := NewOffsetIterator(context.Background(), func(ctx context.Context, offset, limit int) ([]int, error) {
+		calls++
+		return data[offset:min(offset+limit, len(data))], nil
+	}, 3)
+
+	got, err := Collect[int](it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("got %v, want %v", got, data)
+	}
+	if calls != 3 {
+		t.Errorf("fetched %d pages, want 3", calls)
+	}
+}
+
+func TestCursorIteratorIsLazy(t *testing.T) {
+	var cursors []string
+	it := NewCursorIterator(context.Background(), func(ctx context.Context, cursor string) (Page[int], error) {
+		cursors = append(cursors, cursor)
+		n, _ := strconv.Atoi(cursor)
+		if n >= 4 {
+			return Page[int]{Items: []int{n}}, nil
+		}
+		return Page[int]{Items: []int{n, n + 1}, NextCursor: strconv.Itoa(n + 2)}, nil
+	})
+
+	if !it.Next() || it.Value() != 0 {
+		t.Fatalf("first value = %v", it.Value())
+	}
+	if len(cursors) != 1 {
+		t.Fatalf("fetched %d pages before the first page was consumed", len(cursors))
+	}
+	rest, err := Collect[int](it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("got %v, want %v", rest, want)
+	}
+	if want := []string{"", "2", "4"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("cursors %q, want %q", cursors, want)
+	}
+}
+
+func TestPageIteratorFetchError(t *testing.T) {
+	boom := errors.New("boom")
+	it := NewCursorIterator(context.Background(), func(ctx context.Context, cursor string) (Page[int], error) {
+		if cursor == "" {
+			return Page[int]{Items: []int{1}, NextCursor: "next"}, nil
+		}
+		return Page[int]{}, boom
+	})
+	got, err := Collect[int](it)
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v, want %v", err, boom)
+	}
+	if !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("got %v, want [1]", got)
+	}
+	if it.Next() {
+		t.Error("Next after an error returned true")
+	}
+}
+
+func TestPageIteratorCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewOffsetIterator(ctx, func(ctx context.Context, offset, limit int) ([]int, error) {
+		return []int{offset, offset + 1}, nil
+	}, 2)
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Error("Next after cancel returned true")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", it.Err())
+	}
+}
+
+func TestLineIterator(t *testing.T) {
+	it := NewLineIterator(context.Background(), strings.NewReader("a\r\nb\n\nc"))
+	var lines []string
+	for it.Next() {
+		lines = append(lines, strconv.Itoa(it.Line())+":"+it.Value())
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if want := []string{"1:a", "2:b", "3:", "4:c"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewLineIterator(ctx, strings.NewReader("x\n"))
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("canceled iterator: err = %v", it.Err())
+	}
+}
+
+func TestCSVIterator(t *testing.T) {
+	it := NewCSVIterator(context.Background(), strings.NewReader("name,age\nann,30\nbob,41\n"), true)
+	if want := []string{"name", "age"}; !reflect.DeepEqual(it.Header(), want) {
+		t.Errorf("header %q, want %q", it.Header(), want)
+	}
+	var rows []map[string]string
+	for it.Next() {
+		rows = append(rows, it.Map())
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	want := []map[string]string{{"name": "ann", "age": "30"}, {"name": "bob", "age": "41"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %v, want %v", rows, want)
+	}
+
+	it = NewCSVIterator(context.Background(), strings.NewReader("a,b\n1,2\n3,\"x\n"), true)
+	for it.Next() {
+	}
+	var recErr *RecordError
+	if !errors.As(it.Err(), &recErr) || recErr.Line != 3 {
+		t.Errorf("err = %v, want a RecordError on line 3", it.Err())
+	}
+}
+
+func TestJSONLinesIterator(t *testing.T) {
+	type user struct{ Name string }
+	it := NewJSONLinesIterator[user](context.Background(), strings.NewReader("{\"Name\":\"a\"}\n\n{\"Name\":\"b\"}\n{bad}\n"))
+	got, err := Collect[user](it)
+	if want := []user{{"a"}, {"b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	var recErr *RecordError
+	if !errors.As(err, &recErr) || recErr.Line != 4 {
+		t.Errorf("err = %v, want a RecordError on line 4", err)
+	}
+}
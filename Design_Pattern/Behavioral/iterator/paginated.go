@@ -0,0 +1,104 @@
+package iterator
+
+import "context"
+
+// Page is one page of results returned by a cursor based source. An empty
+// NextCursor marks the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// CursorFetcher fetches the page that starts at cursor. The first page is
+// requested with an empty cursor.
+type CursorFetcher[T any] func(ctx context.Context, cursor string) (Page[T], error)
+
+// OffsetFetcher fetches at most limit items starting at offset. A page
+// shorter than limit marks the end of the source.
+type OffsetFetcher[T any] func(ctx context.Context, offset, limit int) ([]T, error)
+
+// PageIterator pulls pages from a source lazily, only fetching the next page
+// once every item of the current one has been consumed.
+type PageIterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context) ([]T, bool, error)
+	items []T
+	index int
+	cur   T
+	done  bool
+	err   error
+}
+
+// NewCursorIterator returns an iterator over a cursor paginated source.
+func NewCursorIterator[T any](ctx context.Context, fetch CursorFetcher[T]) *PageIterator[T] {
+	cursor := ""
+	return &PageIterator[T]{
+		ctx: ctx,
+		fetch: func(ctx context.Context) ([]T, bool, error) {
+			page, err := fetch(ctx, cursor)
+			if err != nil {
+				return nil, false, err
+			}
+			cursor = page.NextCursor
+			return page.Items, cursor != "", nil
+		},
+	}
+}
+
+// NewOffsetIterator returns an iterator over an offset paginated source,
+// requesting pageSize items at a time.
+func NewOffsetIterator[T any](ctx context.Context, fetch OffsetFetcher[T], pageSize int) *PageIterator[T] {
+	if pageSize <= 0 {
+		pageSize = 1
+	}
+	offset := 0
+	return &PageIterator[T]{
+		ctx: ctx,
+		fetch: func(ctx context.Context) ([]T, bool, error) {
+			items, err := fetch(ctx, offset, pageSize)
+			if err != nil {
+				return nil, false, err
+			}
+			offset += len(items)
+			return items, len(items) == pageSize, nil
+		},
+	}
+}
+
+func (p *PageIterator[T]) Next() bool {
+	var zero T
+	p.cur = zero
+	if p.err != nil {
+		return false
+	}
+	for p.index >= len(p.items) {
+		if p.done {
+			return false
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+		items, more, err := p.fetch(p.ctx)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.items, p.index, p.done = items, 0, !more
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	p.cur = p.items[p.index]
+	p.index++
+	return true
+}
+
+func (p *PageIterator[T]) Value() T {
+	return p.cur
+}
+
+func (p *PageIterator[T]) Err() error {
+	return p.err
+}
@@ -0,0 +1,135 @@
+package iterator
+
+import (
+	"context"
+	"sync"
+)
+
+type result[R any] struct {
+	value R
+	err   error
+}
+
+// ParallelIterator yields the results of a Parallel call in input order.
+type ParallelIterator[R any] struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending chan chan result[R]
+	wg      sync.WaitGroup
+	closed  bool
+	cur     R
+	err     error
+}
+
+// Parallel fans the items of src out to workers goroutines running fn and
+// yields the results in the order the items were read from src. At most
+// about 2*workers items are in flight at once. Iteration stops at the first
+// error from src or fn. Close must be called if the iterator is abandoned
+// before it is exhausted.
+func Parallel[T, R any](ctx context.Context, src Iterator[T], workers int, fn func(context.Context, T) (R, error)) *ParallelIterator[R] {
+	if workers <= 0 {
+		workers = 1
+	}
+	p := &ParallelIterator[R]{
+		ctx:     ctx,
+		pending: make(chan chan result[R], workers),
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	type job struct {
+		item T
+		out  chan result[R]
+	}
+	jobs := make(chan job)
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			for j := range jobs {
+				v, err := fn(ctx, j.item)
+				j.out <- result[R]{value: v, err: err}
+			}
+		}()
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(p.pending)
+		defer close(jobs)
+		for src.Next() {
+			out := make(chan result[R], 1)
+			select {
+			case p.pending <- out:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job{item: src.Value(), out: out}:
+			case <-ctx.Done():
+				out <- result[R]{err: ctx.Err()}
+				return
+			}
+		}
+		if err := src.Err(); err != nil {
+			out := make(chan result[R], 1)
+			out <- result[R]{err: err}
+			select {
+			case p.pending <- out:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return p
+}
+
+func (p *ParallelIterator[R]) Next() bool {
+	var zero R
+	p.cur = zero
+	if p.err != nil || p.closed {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		p.Close()
+		return false
+	}
+	out, ok := <-p.pending
+	if !ok {
+		// The producer also stops when the context is canceled.
+		p.err = p.ctx.Err()
+		p.Close()
+		return false
+	}
+	r := <-out
+	if r.err != nil {
+		p.err = r.err
+		p.Close()
+		return false
+	}
+	p.cur = r.value
+	return true
+}
+
+func (p *ParallelIterator[R]) Value() R {
+	return p.cur
+}
+
+func (p *ParallelIterator[R]) Err() error {
+	return p.err
+}
+
+// Close stops the workers and waits for them to exit.
+func (p *ParallelIterator[R]) Close() {
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.cancel()
+	go func() {
+		for range p.pending {
+		}
+	}()
+	p.wg.Wait()
+}
@@ -0,0 +1,146 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotIgnoresLaterWrites(t *testing.T) {
+	c := NewCollection(1, 2, 3)
+	s := c.Snapshot()
+	c.Add(4)
+	c.Set(0, 10)
+	got, _ := Collect[int](s)
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	c := NewCollection(1, 2, 3)
+	f := c.FailFast()
+	if !f.Next() || f.Value() != 1 {
+		t.Fatalf("first value = %v", f.Value())
+	}
+	c.Remove(0)
+	if f.Next() {
+		t.Error("Next after a write returned true")
+	}
+	if f.Err() != ErrConcurrentModification {
+		t.Errorf("err = %v, want ErrConcurrentModification", f.Err())
+	}
+
+	got, err := Collect[int](c.FailFast())
+	if err != nil || !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("unmodified iteration = %v, %v", got, err)
+	}
+}
+
+func TestCollectionConcurrentUse(t *testing.T) {
+	c := NewCollection[int]()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			c.Add(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			s := c.Snapshot()
+			for s.Next() {
+			}
+			f := c.FailFast()
+			for f.Next() {
+			}
+		}
+	}()
+	wg.Wait()
+	if c.Len() != 100 {
+		t.Errorf("len = %d, want 100", c.Len())
+	}
+}
+
+func TestParallelKeepsOrder(t *testing.T) {
+	var in []int
+	for i := 0; i < 200; i++ {
+		in = append(in, i)
+	}
+	it := Parallel[int, int](context.Background(), NewSliceIterator(in), 8, func(ctx context.Context, v int) (int, error) {
+		time.Sleep(time.Duration(v%7) * 10 * time.Microsecond)
+		return v * 2, nil
+	})
+	out, err := Collect[int](it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("got %d results, want %d", len(out), len(in))
+	}
+	for i, v := range out {
+		if v != i*2 {
+			t.Fatalf("out[%d] = %d, want %d", i, v, i*2)
+		}
+	}
+}
+
+func TestParallelClose(t *testing.T) {
+	it := Parallel[int, int](context.Background(), NewSliceIterator(make([]int, 100)), 4, func(ctx context.Context, v int) (int, error) {
+		return v, nil
+	})
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	it.Close()
+	it.Close()
+	if it.Next() {
+		t.Error("Next after Close returned true")
+	}
+}
+
+func TestParallelStopsAtFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	var in []int
+	for i := 0; i < 100; i++ {
+		in = append(in, i)
+	}
+	it := Parallel[int, int](context.Background(), NewSliceIterator(in), 4, func(ctx context.Context, v int) (int, error) {
+		if v == 50 {
+			return 0, boom
+		}
+		return v, nil
+	})
+	out, err := Collect[int](it)
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v, want %v", err, boom)
+	}
+	if len(out) != 50 {
+		t.Errorf("got %d results before the error, want 50", len(out))
+	}
+}
+
+func TestParallelReportsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var in []int
+	for i := 0; i < 100; i++ {
+		in = append(in, i)
+	}
+	it := Parallel[int, int](ctx, NewSliceIterator(in), 2, func(ctx context.Context, v int) (int, error) {
+		return v, nil
+	})
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	for it.Next() {
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", it.Err())
+	}
+}
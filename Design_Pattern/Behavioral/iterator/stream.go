@@ -0,0 +1,198 @@
+package iterator
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RecordError reports a record that could not be read or decoded.
+type RecordError struct {
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// LineIterator yields the lines of a reader without their line endings.
+type LineIterator struct {
+	ctx     context.Context
+	scanner *bufio.Scanner
+	line    int
+	cur     string
+	err     error
+}
+
+// NewLineIterator returns an iterator over the lines read from r.
+func NewLineIterator(ctx context.Context, r io.Reader) *LineIterator {
+	return &LineIterator{ctx: ctx, scanner: bufio.NewScanner(r)}
+}
+
+func (l *LineIterator) Next() bool {
+	l.cur = ""
+	if l.err != nil {
+		return false
+	}
+	if err := l.ctx.Err(); err != nil {
+		l.err = err
+		return false
+	}
+	if !l.scanner.Scan() {
+		if err := l.scanner.Err(); err != nil {
+			l.err = &RecordError{Line: l.line + 1, Err: err}
+		}
+		return false
+	}
+	l.line++
+	l.cur = l.scanner.Text()
+	return true
+}
+
+func (l *LineIterator) Value() string {
+	return l.cur
+}
+
+// Line returns the line number of the current value, starting at 1.
+func (l *LineIterator) Line() int {
+	return l.line
+}
+
+func (l *LineIterator) Err() error {
+	return l.err
+}
+
+// CSVIterator yields the records of a CSV document.
+type CSVIterator struct {
+	ctx    context.Context
+	reader *csv.Reader
+	header []string
+	cur    []string
+	err    error
+}
+
+// NewCSVIterator returns an iterator over the CSV records read from r. When
+// hasHeader is true the first record is consumed as the header and is
+// available from Header instead of being yielded.
+func NewCSVIterator(ctx context.Context, r io.Reader, hasHeader bool) *CSVIterator {
+	c := &CSVIterator{ctx: ctx, reader: csv.NewReader(r)}
+	if hasHeader {
+		header, err := c.reader.Read()
+		if err != nil && !errors.Is(err, io.EOF) {
+			c.err = c.wrap(err)
+		}
+		c.header = header
+	}
+	return c
+}
+
+func (c *CSVIterator) Next() bool {
+	c.cur = nil
+	if c.err != nil {
+		return false
+	}
+	if err := c.ctx.Err(); err != nil {
+		c.err = err
+		return false
+	}
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+	if err != nil {
+		c.err = c.wrap(err)
+		return false
+	}
+	c.cur = record
+	return true
+}
+
+func (c *CSVIterator) wrap(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &RecordError{Line: parseErr.Line, Err: parseErr.Err}
+	}
+	line, _ := c.reader.FieldPos(0)
+	return &RecordError{Line: line, Err: err}
+}
+
+func (c *CSVIterator) Value() []string {
+	return c.cur
+}
+
+// Header returns the header record, or nil if the iterator has no header.
+func (c *CSVIterator) Header() []string {
+	return c.header
+}
+
+// Map returns the current record keyed by header column. It returns nil if
+// the iterator has no header.
+func (c *CSVIterator) Map() map[string]string {
+	if c.header == nil || c.cur == nil {
+		return nil
+	}
+	m := make(map[string]string, len(c.header))
+	for i, name := range c.header {
+		if i < len(c.cur) {
+			m[name] = c.cur[i]
+		}
+	}
+	return m
+}
+
+func (c *CSVIterator) Err() error {
+	return c.err
+}
+
+// JSONLinesIterator decodes one JSON value of type T per line. Blank lines
+// are skipped.
+type JSONLinesIterator[T any] struct {
+	lines *LineIterator
+	cur   T
+	err   error
+}
+
+// NewJSONLinesIterator returns an iterator over the JSON lines read from r.
+func NewJSONLinesIterator[T any](ctx context.Context, r io.Reader) *JSONLinesIterator[T] {
+	return &JSONLinesIterator[T]{lines: NewLineIterator(ctx, r)}
+}
+
+func (j *JSONLinesIterator[T]) Next() bool {
+	var zero T
+	j.cur = zero
+	if j.err != nil {
+		return false
+	}
+	for j.lines.Next() {
+		text := j.lines.Value()
+		if len(text) == 0 {
+			continue
+		}
+		var v T
+		if err := json.Unmarshal([]byte(text), &v); err != nil {
+			j.err = &RecordError{Line: j.lines.Line(), Err: err}
+			return false
+		}
+		j.cur = v
+		return true
+	}
+	j.err = j.lines.Err()
+	return false
+}
+
+func (j *JSONLinesIterator[T]) Value() T {
+	return j.cur
+}
+
+func (j *JSONLinesIterator[T]) Err() error {
+	return j.err
+}
@@ -0,0 +1,89 @@
+// Package builder provides a generic step by step builder. Steps are
+// chainable, required steps are checked when the product is built and
+// directors hold named recipes that can be reused to build new styles.
+package builder
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMissingStep is wrapped by the error Build returns when a required step
+// was never applied.
+var ErrMissingStep = errors.New("missing required step")
+
+// Step is one named construction step applied to the product.
+type Step[T any] struct {
+	Name  string
+	Apply func(*T) error
+}
+
+// Builder assembles a product of type T one step at a time. The product is
+// only handed out by Build, once every required step has been applied.
+type Builder[T any] struct {
+	product  T
+	required []string
+	applied  map[string]bool
+	failed   map[string]bool
+	checks   []func(T) error
+	errs     []error
+}
+
+// New returns a builder that insists on the named steps being applied
+// before Build succeeds.
+func New[T any](required ...string) *Builder[T] {
+	return &Builder[T]{
+		required: required,
+		applied:  make(map[string]bool),
+		failed:   make(map[string]bool),
+	}
+}
+
+// With applies steps in order and returns the builder so calls can be
+// chained. Errors are collected and reported by Build.
+func (b *Builder[T]) With(steps ...Step[T]) *Builder[T] {
+	for _, s := range steps {
+		if err := s.Apply(&b.product); err != nil {
+			b.errs = append(b.errs, fmt.Errorf("step %q: %w", s.Name, err))
+			b.failed[s.Name] = true
+			continue
+		}
+		b.applied[s.Name] = true
+		delete(b.failed, s.Name)
+	}
+	return b
+}
+
+// Check adds a validation run against the finished product by Build.
+func (b *Builder[T]) Check(check func(T) error) *Builder[T] {
+	b.checks = append(b.checks, check)
+	return b
+}
+
+// Applied reports whether the named step has been applied successfully.
+func (b *Builder[T]) Applied(name string) bool {
+	return b.applied[name]
+}
+
+// Build returns the product, or an error describing every failed step,
+// every missing required step and every failed check.
+func (b *Builder[T]) Build() (T, error) {
+	errs := append([]error(nil), b.errs...)
+	for _, name := range b.required {
+		if !b.applied[name] && !b.failed[name] {
+			errs = append(errs, fmt.Errorf("%w %q", ErrMissingStep, name))
+		}
+	}
+	if len(errs) == 0 {
+		for _, check := range b.checks {
+			if err := check(b.product); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		var zero T
+		return zero, errors.Join(errs...)
+	}
+	return b.product, nil
+}
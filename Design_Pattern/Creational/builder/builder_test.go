@@ -0,0 +1,80 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilderReportsMissingAndFailedSteps(t *testing.T) {
+	_, err := NewHouseBuilder().With(Floors(0)).Build()
+	if err == nil {
+		t.Fatal("Build succeeded without the required steps")
+	}
+	if !errors.Is(err, ErrMissingStep) {
+		t.Errorf("err = %v, want it to wrap ErrMissingStep", err)
+	}
+	for _, want := range []string{`"window type"`, `"door type"`, "at least one floor"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q, want it to mention %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), `missing required step "floors"`) {
+		t.Errorf("a failed step is also reported as missing: %q", err)
+	}
+}
+
+func TestBuilderKeepsEarlierStepErrors(t *testing.T) {
+	b := NewHouseBuilder().With(Floors(0), Floors(2), WindowType("w"), DoorType("d"))
+	if !b.Applied(StepFloors) {
+		t.Error("floors not reported as applied")
+	}
+	h, err := b.Build()
+	if err == nil {
+		t.Fatal("the failed floors step was not reported")
+	}
+	if h.Floors != 0 {
+		t.Errorf("Build returned a product alongside an error: %+v", h)
+	}
+}
+
+func TestBuilderChecksRunLast(t *testing.T) {
+	_, err := NewHouseBuilder().With(
+		WindowType("w"), DoorType("d"), Floors(1),
+		WithDimensions(2, 2, 2),
+		WithRoom(Room{Name: "Hall", Width: 3, Length: 3}),
+	).Build()
+	if err == nil || !strings.Contains(err.Error(), "rooms need 9.0 m2") {
+		t.Errorf("err = %v, want the room check to fail", err)
+	}
+}
+
+func TestDirector(t *testing.T) {
+	d := NewHouseDirector()
+	h, err := d.Build("normal", Floors(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Floors != 3 || h.WindowType != "Wooden Window" {
+		t.Errorf("got %+v", h)
+	}
+	if _, err := d.Build("castle"); err == nil {
+		t.Error("unknown recipe built")
+	}
+
+	cabin := func(b *Builder[House]) *Builder[House] {
+		return b.With(WindowType("Log Window"), DoorType("Log Door"), Floors(1))
+	}
+	if err := d.Register("cabin", cabin); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Register("cabin", cabin); err == nil {
+		t.Error("registering a recipe twice succeeded")
+	}
+	if got, want := strings.Join(d.Recipes(), ","), "cabin,igloo,normal"; got != want {
+		t.Errorf("recipes = %s, want %s", got, want)
+	}
+	if h, err := d.Build("cabin"); err != nil || h.DoorType != "Log Door" {
+		t.Errorf("cabin = %+v, %v", h, err)
+	}
+}
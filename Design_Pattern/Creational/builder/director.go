@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Recipe drives a builder through the steps for one style of product.
+type Recipe[T any] func(*Builder[T]) *Builder[T]
+
+// Director keeps named recipes and builds products from them. New styles
+// are added with Register, without changing the builder or the director.
+type Director[T any] struct {
+	mu         sync.RWMutex
+	newBuilder func() *Builder[T]
+	recipes    map[string]Recipe[T]
+}
+
+// NewDirector returns a director that starts every build from a fresh
+// builder returned by newBuilder.
+func NewDirector[T any](newBuilder func() *Builder[T]) *Director[T] {
+	return &Director[T]{
+		newBuilder: newBuilder,
+		recipes:    make(map[string]Recipe[T]),
+	}
+}
+
+// Register adds a recipe under name. It fails if name is already taken.
+func (d *Director[T]) Register(name string, recipe Recipe[T]) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.recipes[name]; ok {
+		return fmt.Errorf("builder: recipe %q already registered", name)
+	}
+	d.recipes[name] = recipe
+	return nil
+}
+
+// Recipes returns the registered recipe names in sorted order.
+func (d *Director[T]) Recipes() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	names := make([]string, 0, len(d.recipes))
+	for name := range d.recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build runs the named recipe on a fresh builder. The extra steps are
+// applied after the recipe and can override what it set.
+func (d *Director[T]) Build(name string, extra ...Step[T]) (T, error) {
+	d.mu.RLock()
+	recipe, ok := d.recipes[name]
+	d.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("builder: unknown recipe %q", name)
+	}
+	return recipe(d.newBuilder()).With(extra...).Build()
+}
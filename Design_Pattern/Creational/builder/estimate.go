@@ -0,0 +1,180 @@
+package builder
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Units used in a bill of materials.
+const (
+	UnitSquareMetre = "m2"
+	UnitEach        = "each"
+)
+
+// LineItem is the quantity of one material needed to build a house.
+type LineItem struct {
+	Material string
+	Unit     string
+	Quantity float64
+}
+
+// BillOfMaterials lists what is needed to build a house.
+type BillOfMaterials []LineItem
+
+// BillOfMaterials works out the materials needed for the walls, floors,
+// roof, windows and doors of the house. Line items for the same material
+// are merged.
+func (h House) BillOfMaterials() BillOfMaterials {
+	d := h.Dimensions
+	floors := float64(h.Floors)
+	var windows, doors int
+	for _, r := range h.Rooms {
+		windows += r.Windows
+		doors += r.Doors
+	}
+
+	var bom BillOfMaterials
+	bom.add(h.Materials.Wall, UnitSquareMetre, 2*(d.Width+d.Length)*d.FloorHeight*floors)
+	bom.add(h.Materials.Floor, UnitSquareMetre, d.Width*d.Length*floors)
+	bom.add(h.Materials.Roof, UnitSquareMetre, d.Width*d.Length)
+	bom.add(h.WindowType, UnitEach, float64(windows))
+	bom.add(h.DoorType, UnitEach, float64(doors))
+	return bom
+}
+
+func (b *BillOfMaterials) add(material, unit string, qty float64) {
+	if material == "" || qty <= 0 {
+		return
+	}
+	for i := range *b {
+		if (*b)[i].Material == material && (*b)[i].Unit == unit {
+			(*b)[i].Quantity += qty
+			return
+		}
+	}
+	*b = append(*b, LineItem{Material: material, Unit: unit, Quantity: qty})
+}
+
+// Price is the cost of one unit of a material.
+type Price struct {
+	Unit      string
+	UnitPrice float64
+}
+
+// PriceTable maps material names to their price.
+type PriceTable map[string]Price
+
+// LoadPriceTable reads a price table from CSV with a header row and the
+// columns material, unit and unit_price.
+func LoadPriceTable(r io.Reader) (PriceTable, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("price table: reading header: %w", err)
+	}
+	col := make(map[string]int)
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"material", "unit", "unit_price"} {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("price table: missing %q column", name)
+		}
+	}
+
+	table := make(PriceTable)
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return table, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("price table: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		material := rec[col["material"]]
+		price, err := strconv.ParseFloat(rec[col["unit_price"]], 64)
+		if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+			return nil, fmt.Errorf("price table: line %d: invalid unit price %q for %q", line, rec[col["unit_price"]], material)
+		}
+		if _, ok := table[material]; ok {
+			return nil, fmt.Errorf("price table: line %d: duplicate material %q", line, material)
+		}
+		table[material] = Price{Unit: rec[col["unit"]], UnitPrice: price}
+	}
+}
+
+// LoadPriceTableFile reads a price table from the CSV file at path.
+func LoadPriceTableFile(path string) (PriceTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadPriceTable(f)
+}
+
+// CostLine is a priced line of a bill of materials.
+type CostLine struct {
+	LineItem
+	UnitPrice float64
+	Cost      float64
+}
+
+// Estimate is the priced bill of materials of a house.
+type Estimate struct {
+	Lines []CostLine
+	Total float64
+}
+
+// Estimate prices every line of bom. It fails if a material is missing from
+// the table or is priced in a different unit.
+func (t PriceTable) Estimate(bom BillOfMaterials) (Estimate, error) {
+	var est Estimate
+	var errs []error
+	for _, item := range bom {
+		price, ok := t[item.Material]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("no price for %q", item.Material))
+			continue
+		case price.Unit != item.Unit:
+			errs = append(errs, fmt.Errorf("%q is priced per %s but measured in %s", item.Material, price.Unit, item.Unit))
+			continue
+		}
+		cost := item.Quantity * price.UnitPrice
+		est.Lines = append(est.Lines, CostLine{LineItem: item, UnitPrice: price.UnitPrice, Cost: cost})
+		est.Total += cost
+	}
+	if len(errs) > 0 {
+		return Estimate{}, errors.Join(errs...)
+	}
+	sort.SliceStable(est.Lines, func(i, j int) bool { return est.Lines[i].Cost > est.Lines[j].Cost })
+	return est, nil
+}
+
+// Estimate prices the bill of materials of the house with t.
+func (h House) Estimate(t PriceTable) (Estimate, error) {
+	return t.Estimate(h.BillOfMaterials())
+}
+
+// Render writes the estimate as an aligned table.
+func (e Estimate) Render(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Material\tQuantity\tUnit\tUnit price\tCost")
+	for _, l := range e.Lines {
+		fmt.Fprintf(tw, "%s\t%.2f\t%s\t%.2f\t%.2f\n", l.Material, l.Quantity, l.Unit, l.UnitPrice, l.Cost)
+	}
+	fmt.Fprintf(tw, "Total\t\t\t\t%.2f\n", e.Total)
+	return tw.Flush()
+}
@@ -0,0 +1,99 @@
+package builder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIglooEstimate(t *testing.T) {
+	prices, err := LoadPriceTableFile("prices.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHouseDirector().Build("igloo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantBOM := BillOfMaterials{
+		{Material: "Snow Block", Unit: UnitSquareMetre, Quantity: 48},
+		{Material: "Packed Snow", Unit: UnitSquareMetre, Quantity: 16},
+		{Material: "Snow Window", Unit: UnitEach, Quantity: 1},
+		{Material: "Snow Door", Unit: UnitEach, Quantity: 1},
+	}
+	if bom := h.BillOfMaterials(); !reflect.DeepEqual(bom, wantBOM) {
+		t.Errorf("bill of materials = %+v, want %+v", bom, wantBOM)
+	}
+
+	est, err := h.Estimate(prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Total != 251 {
+		t.Errorf("total = %.2f, want 251.00", est.Total)
+	}
+	if est.Lines[0].Material != "Snow Block" {
+		t.Errorf("most expensive line = %q, want Snow Block", est.Lines[0].Material)
+	}
+
+	var out strings.Builder
+	if err := est.Render(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Total") || !strings.Contains(out.String(), "251.00") {
+		t.Errorf("rendered estimate lacks the total:\n%s", out.String())
+	}
+}
+
+func TestEstimateErrors(t *testing.T) {
+	prices := PriceTable{
+		"Snow Block":  {Unit: UnitEach, UnitPrice: 4},
+		"Snow Window": {Unit: UnitEach, UnitPrice: 15},
+		"Snow Door":   {Unit: UnitEach, UnitPrice: 20},
+	}
+	h, err := NewHouseDirector().Build("igloo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = h.Estimate(prices)
+	if err == nil {
+		t.Fatal("estimate succeeded with a missing and a mismatched price")
+	}
+	for _, want := range []string{`no price for "Packed Snow"`, `"Snow Block" is priced per each but measured in m2`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	tests := []struct {
+		name, csv, err string
+	}{
+		{"reordered columns", "unit_price,material,unit\n1.5,Sand,m2\n", ""},
+		{"missing column", "material,unit\nSand,m2\n", `missing "unit_price" column`},
+		{"bad price", "material,unit,unit_price\nSand,m2,cheap\n", `line 2: invalid unit price "cheap"`},
+		{"negative price", "material,unit,unit_price\nSand,m2,-1\n", "invalid unit price"},
+		{"NaN price", "material,unit,unit_price\nSand,m2,NaN\n", `invalid unit price "NaN"`},
+		{"infinite price", "material,unit,unit_price\nSand,m2,+Inf\n", `invalid unit price "+Inf"`},
+		{"duplicate", "material,unit,unit_price\nSand,m2,1\nSand,m2,2\n", `line 3: duplicate material "Sand"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := LoadPriceTable(strings.NewReader(tt.csv))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p := table["Sand"]; p.Unit != UnitSquareMetre || p.UnitPrice != 1.5 {
+					t.Errorf("Sand = %+v", p)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Names of the house construction steps.
+const (
+	StepWindowType = "window type"
+	StepDoorType   = "door type"
+	StepFloors     = "floors"
+	StepDimensions = "dimensions"
+	StepMaterials  = "materials"
+	StepRoom       = "room"
+)
+
+// Dimensions of a house in metres. Width and Length describe the footprint
+// shared by every floor.
+type Dimensions struct {
+	Width       float64
+	Length      float64
+	FloorHeight float64
+}
+
+// Materials used for the shell of a house.
+type Materials struct {
+	Wall  string
+	Floor string
+	Roof  string
+}
+
+// Room is one room of a house. Width and Length are in metres.
+type Room struct {
+	Name    string
+	Width   float64
+	Length  float64
+	Windows int
+	Doors   int
+}
+
+// Area returns the floor area of the room in square metres.
+func (r Room) Area() float64 {
+	return r.Width * r.Length
+}
+
+// House is the product built by the house recipes.
+type House struct {
+	WindowType string
+	DoorType   string
+	Floors     int
+	Dimensions Dimensions
+	Materials  Materials
+	Rooms      []Room
+}
+
+// Validate checks that the rooms fit in the house.
+func (h House) Validate() error {
+	var used float64
+	for _, r := range h.Rooms {
+		used += r.Area()
+	}
+	available := h.Dimensions.Width * h.Dimensions.Length * float64(h.Floors)
+	if used > available {
+		return fmt.Errorf("rooms need %.1f m2 but %d floor(s) of %.1f x %.1f only give %.1f m2",
+			used, h.Floors, h.Dimensions.Width, h.Dimensions.Length, available)
+	}
+	return nil
+}
+
+// NewHouseBuilder returns a builder that requires the window, door and
+// floor steps and validates the finished house.
+func NewHouseBuilder() *Builder[House] {
+	return New[House](StepWindowType, StepDoorType, StepFloors).Check(House.Validate)
+}
+
+// WindowType sets the window type of the house.
+func WindowType(window string) Step[House] {
+	return Step[House]{Name: StepWindowType, Apply: func(h *House) error {
+		if window == "" {
+			return errors.New("window type must not be empty")
+		}
+		h.WindowType = window
+		return nil
+	}}
+}
+
+// DoorType sets the door type of the house.
+func DoorType(door string) Step[House] {
+	return Step[House]{Name: StepDoorType, Apply: func(h *House) error {
+		if door == "" {
+			return errors.New("door type must not be empty")
+		}
+		h.DoorType = door
+		return nil
+	}}
+}
+
+// Floors sets the number of floors of the house.
+func Floors(n int) Step[House] {
+	return Step[House]{Name: StepFloors, Apply: func(h *House) error {
+		if n < 1 {
+			return errors.New("a house needs at least one floor")
+		}
+		h.Floors = n
+		return nil
+	}}
+}
+
+// WithDimensions sets the footprint and floor height of the house.
+func WithDimensions(width, length, floorHeight float64) Step[House] {
+	return Step[House]{Name: StepDimensions, Apply: func(h *House) error {
+		if width <= 0 || length <= 0 || floorHeight <= 0 {
+			return fmt.Errorf("dimensions %.1f x %.1f x %.1f must be positive", width, length, floorHeight)
+		}
+		h.Dimensions = Dimensions{Width: width, Length: length, FloorHeight: floorHeight}
+		return nil
+	}}
+}
+
+// WithMaterials sets the wall, floor and roof materials of the house.
+func WithMaterials(wall, floor, roof string) Step[House] {
+	return Step[House]{Name: StepMaterials, Apply: func(h *House) error {
+		if wall == "" || floor == "" || roof == "" {
+			return errors.New("wall, floor and roof materials must all be set")
+		}
+		h.Materials = Materials{Wall: wall, Floor: floor, Roof: roof}
+		return nil
+	}}
+}
+
+// WithRoom adds a room to the house.
+func WithRoom(r Room) Step[House] {
+	return Step[House]{Name: StepRoom, Apply: func(h *House) error {
+		if r.Name == "" || r.Width <= 0 || r.Length <= 0 {
+			return fmt.Errorf("room %q needs a name and a positive size", r.Name)
+		}
+		if r.Windows < 0 || r.Doors < 0 {
+			return fmt.Errorf("room %q has a negative number of windows or doors", r.Name)
+		}
+		h.Rooms = append(h.Rooms, r)
+		return nil
+	}}
+}
+
+// NormalHouse is the recipe for a wooden two floor house.
+func NormalHouse(b *Builder[House]) *Builder[House] {
+	return b.With(
+		WindowType("Wooden Window"),
+		DoorType("Wooden Door"),
+		Floors(2),
+		WithDimensions(10, 8, 3),
+		WithMaterials("Timber", "Hardwood", "Clay Tile"),
+		WithRoom(Room{Name: "Living", Width: 5, Length: 6, Windows: 2, Doors: 2}),
+		WithRoom(Room{Name: "Kitchen", Width: 5, Length: 4, Windows: 1, Doors: 1}),
+		WithRoom(Room{Name: "Bedroom", Width: 5, Length: 5, Windows: 2, Doors: 1}),
+		WithRoom(Room{Name: "Bedroom", Width: 4, Length: 4, Windows: 1, Doors: 1}),
+		WithRoom(Room{Name: "Bathroom", Width: 3, Length: 2, Windows: 1, Doors: 1}),
+	)
+}
+
+// IglooHouse is the recipe for a single floor snow house.
+func IglooHouse(b *Builder[House]) *Builder[House] {
+	return b.With(
+		WindowType("Snow Window"),
+		DoorType("Snow Door"),
+		Floors(1),
+		WithDimensions(4, 4, 2),
+		WithMaterials("Snow Block", "Packed Snow", "Snow Block"),
+		WithRoom(Room{Name: "Living", Width: 4, Length: 4, Windows: 1, Doors: 1}),
+	)
+}
+
+// NewHouseDirector returns a director with the "normal" and "igloo" recipes
+// registered.
+func NewHouseDirector() *Director[House] {
+	d := NewDirector(NewHouseBuilder)
+	d.Register("normal", NormalHouse)
+	d.Register("igloo", IglooHouse)
+	return d
+}
@@ -0,0 +1,15 @@
+// Package command implements the restaurant example from command.md with
+// undoable commands and an undo/redo history.
+package command
+
+// Command is a task that can be executed.
+type Command interface {
+	Execute() error
+}
+
+// Undoable is a command whose effect can be reverted after it has been
+// executed.
+type Undoable interface {
+	Command
+	Undo() error
+}
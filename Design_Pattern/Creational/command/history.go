@@ -0,0 +1,138 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNothingToUndo is returned by History.Undo when the undo stack is empty.
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned by History.Redo when the redo stack is empty.
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// History executes commands and keeps them on undo and redo stacks.
+type History struct {
+	limit int
+	undo  []Undoable
+	redo  []Undoable
+}
+
+// NewHistory returns a history that remembers at most limit commands. The
+// oldest command is forgotten once the limit is reached. A limit of zero or
+// less means no limit.
+func NewHistory(limit int) *History {
+	return &History{limit: limit}
+}
+
+// Execute runs cmd and records it for undo. Executing a new command clears
+// the redo stack. A command that fails is not recorded.
+func (h *History) Execute(cmd Undoable) error {
+	if err := cmd.Execute(); err != nil {
+		return err
+	}
+	h.push(cmd)
+	h.redo = nil
+	return nil
+}
+
+// Undo reverts the most recently executed command.
+func (h *History) Undo() error {
+	if len(h.undo) == 0 {
+		return ErrNothingToUndo
+	}
+	cmd := h.undo[len(h.undo)-1]
+	if err := cmd.Undo(); err != nil {
+		return fmt.Errorf("undo %v: %w", cmd, err)
+	}
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, cmd)
+	return nil
+}
+
+// Redo executes the most recently undone command again.
+func (h *History) Redo() error {
+	if len(h.redo) == 0 {
+		return ErrNothingToRedo
+	}
+	cmd := h.redo[len(h.redo)-1]
+	if err := cmd.Execute(); err != nil {
+		return fmt.Errorf("redo %v: %w", cmd, err)
+	}
+	h.redo = h.redo[:len(h.redo)-1]
+	h.push(cmd)
+	return nil
+}
+
+// CanUndo reports whether there is a command to undo.
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo reports whether there is a command to redo.
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+func (h *History) push(cmd Undoable) {
+	h.undo = append(h.undo, cmd)
+	if h.limit > 0 && len(h.undo) > h.limit {
+		h.undo = append(h.undo[:0], h.undo[len(h.undo)-h.limit:]...)
+	}
+}
+
+// Macro groups several commands into one undoable unit.
+type Macro struct {
+	Name     string
+	Commands []Undoable
+}
+
+// NewMacro returns a macro that runs cmds in order.
+func NewMacro(name string, cmds ...Undoable) *Macro {
+	return &Macro{Name: name, Commands: cmds}
+}
+
+// Execute runs every command in order. If one fails, the commands that
+// already ran are undone so the macro has no effect.
+func (m *Macro) Execute() error {
+	return m.ExecuteContext(context.Background())
+}
+
+// ExecuteContext is like Execute but stops before the next command once ctx
+// is done, undoing the commands that already ran.
+func (m *Macro) ExecuteContext(ctx context.Context) error {
+	for i, cmd := range m.Commands {
+		err := ctx.Err()
+		if err == nil {
+			err = execute(ctx, cmd)
+		}
+		if err != nil {
+			err = fmt.Errorf("macro %s: %v: %w", m.Name, cmd, err)
+			if rbErr := undoAll(m.Commands[:i]); rbErr != nil {
+				return errors.Join(err, rbErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Undo reverts every command in reverse order.
+func (m *Macro) Undo() error {
+	return undoAll(m.Commands)
+}
+
+func (m *Macro) String() string {
+	return m.Name
+}
+
+func undoAll(cmds []Undoable) error {
+	for i := len(cmds) - 1; i >= 0; i-- {
+		if err := cmds[i].Undo(); err != nil {
+			return fmt.Errorf("undo %v: %w", cmds[i], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	r := NewRestaurant()
+	h := NewHistory(0)
+	if err := h.Execute(r.MakePizza(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Execute(r.MakeSalad(2)); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.CleanedDishes(); got != 5 {
+		t.Fatalf("cleaned = %d, want 5", got)
+	}
+
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.CleanedDishes(); got != 7 {
+		t.Errorf("after undo cleaned = %d, want 7", got)
+	}
+	if err := h.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.CleanedDishes(); got != 5 {
+		t.Errorf("after redo cleaned = %d, want 5", got)
+	}
+
+	h.Undo()
+	if err := h.Execute(r.CleanDishes()); err != nil {
+		t.Fatal(err)
+	}
+	if h.CanRedo() {
+		t.Error("executing a command did not clear the redo stack")
+	}
+	if err := h.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Redo = %v, want ErrNothingToRedo", err)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	r := NewRestaurant()
+	h := NewHistory(2)
+	for _, n := range []int{1, 2, 3} {
+		if err := h.Execute(r.MakePizza(n)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Undo()
+	h.Undo()
+	if err := h.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("third Undo = %v, want ErrNothingToUndo", err)
+	}
+	if got := r.CleanedDishes(); got != 9 {
+		t.Errorf("cleaned = %d, want 9: the oldest command should stay applied", got)
+	}
+}
+
+func TestHistoryFailedCommandNotRecorded(t *testing.T) {
+	r := NewRestaurant()
+	h := NewHistory(0)
+	if err := h.Execute(r.MakePizza(11)); !errors.Is(err, ErrNotEnoughDishes) {
+		t.Errorf("err = %v, want ErrNotEnoughDishes", err)
+	}
+	if h.CanUndo() {
+		t.Error("a failed command was recorded")
+	}
+}
+
+func TestInvalidOrder(t *testing.T) {
+	r := NewRestaurant()
+	for _, cmd := range []Command{r.MakePizza(0), r.MakeSalad(-2)} {
+		if err := cmd.Execute(); !errors.Is(err, ErrInvalidOrder) {
+			t.Errorf("%v: err = %v, want ErrInvalidOrder", cmd, err)
+		}
+	}
+	if got := r.CleanedDishes(); got != 10 {
+		t.Errorf("cleaned = %d, want 10", got)
+	}
+}
+
+func TestMacroRollsBack(t *testing.T) {
+	r := NewRestaurant()
+	m := NewMacro("dinner", r.MakePizza(4), r.MakeSalad(4), r.MakePizza(4))
+	if err := m.Execute(); !errors.Is(err, ErrNotEnoughDishes) {
+		t.Fatalf("err = %v, want ErrNotEnoughDishes", err)
+	}
+	if got := r.CleanedDishes(); got != 10 {
+		t.Errorf("cleaned = %d after a failed macro, want 10", got)
+	}
+
+	m = NewMacro("lunch", r.MakePizza(2), r.MakeSalad(3))
+	h := NewHistory(0)
+	if err := h.Execute(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.CleanedDishes(); got != 5 {
+		t.Errorf("cleaned = %d, want 5", got)
+	}
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.CleanedDishes(); got != 10 {
+		t.Errorf("cleaned = %d after undoing the macro, want 10", got)
+	}
+}
@@ -0,0 +1,275 @@
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Journaled is a command that can be written to a journal and rebuilt from
+// it. JournalParams must marshal to JSON.
+type Journaled interface {
+	Command
+	JournalType() string
+	JournalParams() any
+}
+
+// Entry is one executed command in the journal.
+type Entry struct {
+	Seq    uint64          `json:"seq"`
+	Time   time.Time       `json:"time"`
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Checkpoint is a snapshot of the state after the entry with sequence
+// number Seq. Replay starts after it.
+type Checkpoint struct {
+	Seq   uint64          `json:"seq"`
+	State json.RawMessage `json:"state"`
+}
+
+// DecodeFunc rebuilds the command recorded in an entry.
+type DecodeFunc func(e Entry) (Command, error)
+
+// Journal is an append-only log of executed commands, stored as JSON lines
+// in a file, with an optional checkpoint stored next to it.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	seq  uint64
+	size int64 // bytes up to the end of the last complete entry
+}
+
+// OpenJournal opens the journal at path, creating it if needed. Numbering
+// continues from the last entry or checkpoint found.
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{path: path}
+	cp, err := j.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if cp != nil {
+		j.seq = cp.Seq
+	}
+	entries, size, err := j.read(0)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(entries); n > 0 && entries[n-1].Seq > j.seq {
+		j.seq = entries[n-1].Seq
+	}
+	j.f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// Drop a torn last line so new entries start on a line of their own.
+	if err := j.f.Truncate(size); err != nil {
+		j.f.Close()
+		return nil, err
+	}
+	j.size = size
+	return j, nil
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.f.Close()
+}
+
+// Execute runs cmd and, if it succeeds, appends it to the journal. The
+// journal lock is held for both so entries are in execution order. If the
+// entry cannot be written and cmd is Undoable, it is undone so the state
+// does not get ahead of the journal.
+func (j *Journal) Execute(cmd Journaled) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := cmd.Execute(); err != nil {
+		return err
+	}
+	err := j.append(cmd)
+	if u, ok := cmd.(Undoable); err != nil && ok {
+		if undoErr := u.Undo(); undoErr != nil {
+			return errors.Join(err, fmt.Errorf("journal: undoing %s: %w", cmd.JournalType(), undoErr))
+		}
+	}
+	return err
+}
+
+func (j *Journal) append(cmd Journaled) error {
+	e := Entry{Seq: j.seq + 1, Time: time.Now().UTC(), Type: cmd.JournalType()}
+	if p := cmd.JournalParams(); p != nil {
+		params, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("journal: encoding %s: %w", e.Type, err)
+		}
+		e.Params = params
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("journal: encoding %s: %w", e.Type, err)
+	}
+	line = append(line, '\n')
+	if _, err := j.f.Write(line); err != nil {
+		// Drop whatever part of the line made it to the file, so the next
+		// entry does not run into it.
+		return errors.Join(fmt.Errorf("journal: %w", err), j.f.Truncate(j.size))
+	}
+	if err := j.f.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("journal: %w", err), j.f.Truncate(j.size))
+	}
+	j.seq = e.Seq
+	j.size += int64(len(line))
+	return nil
+}
+
+// Entries returns the entries with a sequence number greater than after.
+// A torn last line, left by a crash in the middle of a write, is ignored.
+func (j *Journal) Entries(after uint64) ([]Entry, error) {
+	entries, _, err := j.read(after)
+	return entries, err
+}
+
+// read returns the entries after the given sequence number and the size of
+// the journal up to the end of its last complete line.
+func (j *Journal) read(after uint64) ([]Entry, int64, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	var size int64
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Anything after the last newline was never fully written.
+			return entries, size, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, 0, fmt.Errorf("journal: line %d: %w", line, err)
+		}
+		size += int64(len(data))
+		if e.Seq > after {
+			entries = append(entries, e)
+		}
+	}
+}
+
+// Replay rebuilds and executes every entry after the checkpoint, if any.
+// restore is called with the checkpoint state first. It returns the number
+// of entries replayed.
+func (j *Journal) Replay(restore func(state json.RawMessage) error, decode DecodeFunc) (int, error) {
+	cp, err := j.loadCheckpoint()
+	if err != nil {
+		return 0, err
+	}
+	var after uint64
+	if cp != nil {
+		if err := restore(cp.State); err != nil {
+			return 0, fmt.Errorf("journal: restoring checkpoint %d: %w", cp.Seq, err)
+		}
+		after = cp.Seq
+	}
+	entries, err := j.Entries(after)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range entries {
+		cmd, err := decode(e)
+		if err != nil {
+			return i, fmt.Errorf("journal: entry %d: %w", e.Seq, err)
+		}
+		if err := cmd.Execute(); err != nil {
+			return i, fmt.Errorf("journal: replaying entry %d (%s): %w", e.Seq, e.Type, err)
+		}
+	}
+	return len(entries), nil
+}
+
+// Checkpoint saves state as of the last entry and truncates the journal, so
+// a later Replay only has to run the commands executed after this call.
+// state is called with the journal locked, so no command runs in between.
+func (j *Journal) Checkpoint(state func() any) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	data, err := json.Marshal(state())
+	if err != nil {
+		return fmt.Errorf("journal: encoding checkpoint: %w", err)
+	}
+	cp, err := json.Marshal(Checkpoint{Seq: j.seq, State: data})
+	if err != nil {
+		return fmt.Errorf("journal: encoding checkpoint: %w", err)
+	}
+
+	// Write the checkpoint to a temporary file and rename it over the old
+	// one, so a crash leaves either the old or the new checkpoint.
+	tmp := j.checkpointPath() + ".tmp"
+	if err := writeFileSync(tmp, cp); err != nil {
+		return fmt.Errorf("journal: writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, j.checkpointPath()); err != nil {
+		return fmt.Errorf("journal: writing checkpoint: %w", err)
+	}
+
+	// Entries up to j.seq are covered by the checkpoint. If we crash before
+	// the truncate, Replay skips them anyway.
+	if err := j.f.Truncate(0); err != nil {
+		return fmt.Errorf("journal: truncating: %w", err)
+	}
+	j.size = 0
+	return j.f.Sync()
+}
+
+func (j *Journal) checkpointPath() string {
+	return j.path + ".checkpoint"
+}
+
+func (j *Journal) loadCheckpoint() (*Checkpoint, error) {
+	data, err := os.ReadFile(j.checkpointPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("journal: reading checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
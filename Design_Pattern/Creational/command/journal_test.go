@@ -0,0 +1,119 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalRecover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kitchen.journal")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRestaurant()
+	for _, cmd := range []Journaled{r.MakePizza(3), r.MakeSalad(2), r.CleanDishes(), r.MakePizza(4)} {
+		if err := j.Execute(cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	j.Close()
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	got, err := RecoverRestaurant(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State() != r.State() {
+		t.Errorf("recovered %+v, want %+v", got.State(), r.State())
+	}
+}
+
+func TestJournalCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kitchen.journal")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	r := NewRestaurant()
+	j.Execute(r.MakePizza(3))
+	j.Execute(r.MakeSalad(2))
+	if err := j.Checkpoint(func() any { return r.State() }); err != nil {
+		t.Fatal(err)
+	}
+	j.Execute(r.MakePizza(1))
+
+	entries, err := j.Entries(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Seq != 3 {
+		t.Fatalf("entries after checkpoint = %+v, want only seq 3", entries)
+	}
+	got, err := RecoverRestaurant(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State() != r.State() {
+		t.Errorf("recovered %+v, want %+v", got.State(), r.State())
+	}
+}
+
+func TestJournalTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kitchen.journal")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRestaurant()
+	j.Execute(r.MakePizza(3))
+	j.Close()
+
+	// Simulate a crash in the middle of writing the second entry.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":2,"type":"make_sal`)
+	f.Close()
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if err := j.Execute(r.MakeSalad(2)); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := j.Entries(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Seq != 2 || entries[1].Type != "make_salad" {
+		t.Errorf("entries = %+v, want make_pizza then make_salad", entries)
+	}
+}
+
+func TestJournalUndoesCommandThatCannotBeWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kitchen.journal")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.f.Close() // make every write fail
+
+	r := NewRestaurant()
+	before := r.State()
+	if err := j.Execute(r.MakePizza(3)); err == nil {
+		t.Fatal("Execute succeeded with a closed journal")
+	}
+	if r.State() != before {
+		t.Errorf("state = %+v after failed write, want %+v", r.State(), before)
+	}
+}
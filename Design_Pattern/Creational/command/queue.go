@@ -0,0 +1,209 @@
+package command
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueClosed is returned by Queue.Submit after the queue has been closed.
+	ErrQueueClosed = errors.New("command queue is closed")
+	// ErrTimeoutUnsupported is returned by Queue.Submit when a timeout is
+	// set on a command that does not implement ContextCommand, since such a
+	// command could not be stopped once its timeout expired.
+	ErrTimeoutUnsupported = errors.New("command cannot be cancelled, so it cannot have a timeout")
+)
+
+// ContextCommand is a command that can be cancelled. The queue passes it a
+// context that expires when the command's timeout does.
+type ContextCommand interface {
+	ExecuteContext(ctx context.Context) error
+}
+
+// execute runs cmd with ctx if it is a ContextCommand.
+func execute(ctx context.Context, cmd Command) error {
+	if cc, ok := cmd.(ContextCommand); ok {
+		return cc.ExecuteContext(ctx)
+	}
+	return cmd.Execute()
+}
+
+// CommandFunc adapts a function to both Command and ContextCommand.
+type CommandFunc func(ctx context.Context) error
+
+func (f CommandFunc) Execute() error {
+	return f(context.Background())
+}
+
+func (f CommandFunc) ExecuteContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+// JobOption configures a submitted command.
+type JobOption func(*job)
+
+// WithPriority sets the priority of a command. Commands with a higher
+// priority are picked up first, and equal priorities run in submission
+// order. The default priority is 0.
+func WithPriority(p int) JobOption {
+	return func(j *job) {
+		j.priority = p
+	}
+}
+
+// WithTimeout bounds how long a command may run. Only commands that
+// implement ContextCommand accept a timeout.
+func WithTimeout(d time.Duration) JobOption {
+	return func(j *job) {
+		j.timeout = d
+	}
+}
+
+// Result is the outcome of one command run by the queue.
+type Result struct {
+	ID       int
+	Command  Command
+	Cook     int
+	Err      error
+	Duration time.Duration
+}
+
+type job struct {
+	id       int
+	cmd      Command
+	priority int
+	timeout  time.Duration
+}
+
+type jobHeap []*job
+
+func (h jobHeap) Len() int { return len(h) }
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].id < h[j].id
+}
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *jobHeap) Push(x any)   { *h = append(*h, x.(*job)) }
+func (h *jobHeap) Pop() any {
+	old := *h
+	j := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return j
+}
+
+// Queue is a job queue served by a pool of cooks. Each cook is a goroutine
+// that takes the highest priority command from the queue and executes it.
+type Queue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	jobs    jobHeap
+	nextID  int
+	closed  bool
+	results []Result
+	wg      sync.WaitGroup
+}
+
+// NewQueue starts a queue with the given number of cooks.
+func NewQueue(cooks int) *Queue {
+	if cooks <= 0 {
+		cooks = 1
+	}
+	q := &Queue{}
+	q.cond = sync.NewCond(&q.mu)
+	q.wg.Add(cooks)
+	for i := 0; i < cooks; i++ {
+		go q.cook(i)
+	}
+	return q
+}
+
+// Submit adds cmd to the queue and returns the ID its Result will carry.
+func (q *Queue) Submit(cmd Command, opts ...JobOption) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return 0, ErrQueueClosed
+	}
+	j := &job{cmd: cmd}
+	for _, opt := range opts {
+		opt(j)
+	}
+	if _, ok := cmd.(ContextCommand); j.timeout > 0 && !ok {
+		return 0, ErrTimeoutUnsupported
+	}
+	q.nextID++
+	j.id = q.nextID
+	heap.Push(&q.jobs, j)
+	q.cond.Signal()
+	return j.id, nil
+}
+
+// Close stops accepting commands, waits for the cooks to finish everything
+// already queued and returns the results ordered by ID.
+func (q *Queue) Close() []Result {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	results := append([]Result(nil), q.results...)
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return results
+}
+
+// Errors returns the results of Close that failed.
+func Errors(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+func (q *Queue) cook(id int) {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		for len(q.jobs) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.jobs) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		j := heap.Pop(&q.jobs).(*job)
+		q.mu.Unlock()
+
+		start := time.Now()
+		err := run(j)
+		r := Result{ID: j.id, Command: j.cmd, Cook: id, Err: err, Duration: time.Since(start)}
+
+		q.mu.Lock()
+		q.results = append(q.results, r)
+		q.mu.Unlock()
+	}
+}
+
+// run executes the command of j within its timeout. Submit only accepts a
+// timeout for a ContextCommand, which is stopped through its context.
+func run(j *job) error {
+	ctx := context.Background()
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+	return execute(ctx, j.cmd)
+}
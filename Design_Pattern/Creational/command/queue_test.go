@@ -0,0 +1,110 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// plainCommand implements Command but not ContextCommand.
+type plainCommand struct{}
+
+func (plainCommand) Execute() error { return nil }
+
+func TestQueuePriorityOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []int
+	record := func(n int) CommandFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, n)
+			return nil
+		}
+	}
+
+	// A single cook blocked on the first command sees the others queued.
+	release := make(chan struct{})
+	q := NewQueue(1)
+	q.Submit(CommandFunc(func(ctx context.Context) error {
+		<-release
+		return nil
+	}))
+	q.Submit(record(1), WithPriority(0))
+	q.Submit(record(2), WithPriority(5))
+	q.Submit(record(3), WithPriority(5))
+	q.Submit(record(4), WithPriority(1))
+	close(release)
+	q.Close()
+
+	want := []int{2, 3, 4, 1}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestQueueResults(t *testing.T) {
+	r := NewRestaurant()
+	q := NewQueue(4)
+	for i := 0; i < 10; i++ {
+		if _, err := q.Submit(r.MakePizza(1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := q.Close()
+	if len(results) != 10 {
+		t.Fatalf("got %d results, want 10", len(results))
+	}
+	for i, res := range results {
+		if res.ID != i+1 {
+			t.Errorf("results[%d].ID = %d, want %d", i, res.ID, i+1)
+		}
+	}
+	failed := Errors(results)
+	for _, res := range failed {
+		if !errors.Is(res.Err, ErrNotEnoughDishes) {
+			t.Errorf("command %d: %v, want ErrNotEnoughDishes", res.ID, res.Err)
+		}
+	}
+	if got := r.CleanedDishes(); got < 0 || got != r.TotalDishes()-(10-len(failed)) {
+		t.Errorf("cleaned = %d with %d failures", got, len(failed))
+	}
+
+	if _, err := q.Submit(r.CleanDishes()); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Submit after Close = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestQueueTimeout(t *testing.T) {
+	q := NewQueue(1)
+	q.Submit(CommandFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithTimeout(10*time.Millisecond))
+	results := q.Close()
+	if len(results) != 1 || !errors.Is(results[0].Err, context.DeadlineExceeded) {
+		t.Fatalf("results = %+v, want DeadlineExceeded", results)
+	}
+}
+
+func TestQueueRejectsTimeoutOnPlainCommand(t *testing.T) {
+	q := NewQueue(1)
+	defer q.Close()
+	if _, err := q.Submit(plainCommand{}, WithTimeout(time.Second)); !errors.Is(err, ErrTimeoutUnsupported) {
+		t.Errorf("Submit = %v, want ErrTimeoutUnsupported", err)
+	}
+	if _, err := q.Submit(plainCommand{}); err != nil {
+		t.Errorf("Submit without timeout = %v", err)
+	}
+	r := NewRestaurant()
+	if _, err := q.Submit(NewMacro("lunch", r.MakePizza(1)), WithTimeout(time.Second)); err != nil {
+		t.Errorf("Submit macro with timeout = %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotEnoughDishes is returned when a dish is ordered and there are not
+// enough clean dishes to serve it.
+var ErrNotEnoughDishes = errors.New("not enough clean dishes")
+
+// ErrInvalidOrder is returned when a dish is ordered fewer than once.
+var ErrInvalidOrder = errors.New("an order needs at least one dish")
+
+// Restaurant contains the total dishes and the total cleaned dishes. It is
+// safe for concurrent use, so several cooks can work in it at once.
+type Restaurant struct {
+	mu            sync.Mutex
+	totalDishes   int
+	cleanedDishes int
+}
+
+// NewRestaurant returns a restaurant with 10 dishes, all of them clean.
+func NewRestaurant() *Restaurant {
+	const totalDishes = 10
+	return &Restaurant{
+		totalDishes:   totalDishes,
+		cleanedDishes: totalDishes,
+	}
+}
+
+// TotalDishes returns the number of dishes in the restaurant.
+func (r *Restaurant) TotalDishes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.totalDishes
+}
+
+// CleanedDishes returns the number of clean dishes.
+func (r *Restaurant) CleanedDishes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cleanedDishes
+}
+
+// MakePizza returns a command that makes n pizzas.
+func (r *Restaurant) MakePizza(n int) *MakePizzaCommand {
+	return &MakePizzaCommand{n: n, restaurant: r}
+}
+
+// MakeSalad returns a command that makes n salads.
+func (r *Restaurant) MakeSalad(n int) *MakeSaladCommand {
+	return &MakeSaladCommand{n: n, restaurant: r}
+}
+
+// CleanDishes returns a command that washes every dish.
+func (r *Restaurant) CleanDishes() *CleanDishesCommand {
+	return &CleanDishesCommand{restaurant: r}
+}
+
+func (r *Restaurant) useDishes(n int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n > r.cleanedDishes {
+		return fmt.Errorf("%w: need %d, have %d", ErrNotEnoughDishes, n, r.cleanedDishes)
+	}
+	r.cleanedDishes -= n
+	return nil
+}
+
+func (r *Restaurant) returnDishes(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanedDishes = min(r.cleanedDishes+n, r.totalDishes)
+}
+
+// cleanAll marks every dish clean and returns how many were clean before.
+func (r *Restaurant) cleanAll() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	before := r.cleanedDishes
+	r.cleanedDishes = r.totalDishes
+	return before
+}
+
+func (r *Restaurant) setCleaned(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanedDishes = n
+}
+
+// MakePizzaCommand uses up one clean dish per pizza.
+type MakePizzaCommand struct {
+	n          int
+	restaurant *Restaurant
+}
+
+func (c *MakePizzaCommand) Execute() error {
+	if c.n < 1 {
+		return fmt.Errorf("%w: %d pizzas", ErrInvalidOrder, c.n)
+	}
+	return c.restaurant.useDishes(c.n)
+}
+
+// ExecuteContext executes the command unless ctx is already done. The
+// command itself never blocks, so once started it is not interrupted.
+func (c *MakePizzaCommand) ExecuteContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Execute()
+}
+
+func (c *MakePizzaCommand) Undo() error {
+	c.restaurant.returnDishes(c.n)
+	return nil
+}
+
+func (c *MakePizzaCommand) String() string {
+	return fmt.Sprintf("make %d pizzas", c.n)
+}
+
+// MakeSaladCommand uses up one clean dish per salad.
+type MakeSaladCommand struct {
+	n          int
+	restaurant *Restaurant
+}
+
+func (c *MakeSaladCommand) Execute() error {
+	if c.n < 1 {
+		return fmt.Errorf("%w: %d salads", ErrInvalidOrder, c.n)
+	}
+	return c.restaurant.useDishes(c.n)
+}
+
+func (c *MakeSaladCommand) ExecuteContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Execute()
+}
+
+func (c *MakeSaladCommand) Undo() error {
+	c.restaurant.returnDishes(c.n)
+	return nil
+}
+
+func (c *MakeSaladCommand) String() string {
+	return fmt.Sprintf("make %d salads", c.n)
+}
+
+// CleanDishesCommand resets the cleaned dishes to the total dishes. Undo
+// puts back the number of clean dishes there was before.
+type CleanDishesCommand struct {
+	restaurant *Restaurant
+	before     int
+}
+
+func (c *CleanDishesCommand) Execute() error {
+	c.before = c.restaurant.cleanAll()
+	return nil
+}
+
+func (c *CleanDishesCommand) ExecuteContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Execute()
+}
+
+func (c *CleanDishesCommand) Undo() error {
+	c.restaurant.setCleaned(c.before)
+	return nil
+}
+
+func (c *CleanDishesCommand) String() string {
+	return "clean dishes"
+}
+
+// RestaurantState is the part of a Restaurant saved in a journal checkpoint.
+type RestaurantState struct {
+	TotalDishes   int `json:"total_dishes"`
+	CleanedDishes int `json:"cleaned_dishes"`
+}
+
+// State returns a snapshot of the restaurant.
+func (r *Restaurant) State() RestaurantState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RestaurantState{TotalDishes: r.totalDishes, CleanedDishes: r.cleanedDishes}
+}
+
+// Restore replaces the state of the restaurant with s.
+func (r *Restaurant) Restore(s RestaurantState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.totalDishes = s.TotalDishes
+	r.cleanedDishes = s.CleanedDishes
+}
+
+type dishParams struct {
+	N int `json:"n"`
+}
+
+func (c *MakePizzaCommand) JournalType() string { return "make_pizza" }
+func (c *MakePizzaCommand) JournalParams() any  { return dishParams{N: c.n} }
+
+func (c *MakeSaladCommand) JournalType() string { return "make_salad" }
+func (c *MakeSaladCommand) JournalParams() any  { return dishParams{N: c.n} }
+
+func (c *CleanDishesCommand) JournalType() string { return "clean_dishes" }
+func (c *CleanDishesCommand) JournalParams() any  { return nil }
+
+// Decode rebuilds a restaurant command recorded in a journal entry.
+func (r *Restaurant) Decode(e Entry) (Command, error) {
+	switch e.Type {
+	case "make_pizza", "make_salad":
+		var p dishParams
+		if err := json.Unmarshal(e.Params, &p); err != nil {
+			return nil, err
+		}
+		if e.Type == "make_pizza" {
+			return r.MakePizza(p.N), nil
+		}
+		return r.MakeSalad(p.N), nil
+	case "clean_dishes":
+		return r.CleanDishes(), nil
+	}
+	return nil, fmt.Errorf("unknown command type %q", e.Type)
+}
+
+// RecoverRestaurant rebuilds a restaurant from the checkpoint and entries
+// of journal.
+func RecoverRestaurant(journal *Journal) (*Restaurant, error) {
+	r := NewRestaurant()
+	restore := func(state json.RawMessage) error {
+		var s RestaurantState
+		if err := json.Unmarshal(state, &s); err != nil {
+			return err
+		}
+		r.Restore(s)
+		return nil
+	}
+	if _, err := journal.Replay(restore, r.Decode); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
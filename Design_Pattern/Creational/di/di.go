@@ -0,0 +1,287 @@
+// Package di is a small dependency injection container, an alternative to
+// package level getInstance singletons. Constructors are registered with a
+// lifetime and resolved by type.
+package di
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Lifetime says how long a resolved instance lives.
+type Lifetime int
+
+const (
+	// Singleton instances are created once per container.
+	Singleton Lifetime = iota
+	// Transient instances are created on every resolve. The container
+	// closes those resolved from a scope when the scope is closed.
+	Transient
+	// Scoped instances are created once per scope and can only be
+	// resolved from a scope.
+	Scoped
+)
+
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	}
+	return fmt.Sprintf("Lifetime(%d)", int(l))
+}
+
+var (
+	// ErrClosed is returned when resolving from a closed container or scope.
+	ErrClosed = errors.New("di: container closed")
+	// ErrNotRegistered is wrapped by the error returned when a type has
+	// no constructor.
+	ErrNotRegistered = errors.New("di: no constructor registered")
+)
+
+// CycleError is returned when constructors depend on each other in a
+// loop. Path starts and ends with the same type.
+type CycleError struct {
+	Path []reflect.Type
+}
+
+func (e *CycleError) Error() string {
+	names := make([]string, len(e.Path))
+	for i, t := range e.Path {
+		names[i] = t.String()
+	}
+	return "di: dependency cycle: " + strings.Join(names, " -> ")
+}
+
+type provider struct {
+	lifetime Lifetime
+	build    func(r *Resolver) (any, error)
+}
+
+// Container holds constructors and the singletons created from them.
+type Container struct {
+	// mu serialises resolution, so a singleton is only ever built once
+	// and concurrent resolves cannot deadlock on each other.
+	mu        sync.Mutex
+	providers map[reflect.Type]*provider
+	instances
+}
+
+// instances are the cached instances and closers of a container or scope.
+type instances struct {
+	cache   map[reflect.Type]any
+	closers []io.Closer
+	closed  bool
+}
+
+func (in *instances) track(v any) {
+	if c, ok := v.(io.Closer); ok {
+		in.closers = append(in.closers, c)
+	}
+}
+
+// close closes the tracked instances in reverse order of creation.
+func (in *instances) close() error {
+	if in.closed {
+		return nil
+	}
+	in.closed = true
+	var errs []error
+	for i := len(in.closers) - 1; i >= 0; i-- {
+		if err := in.closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	in.closers = nil
+	in.cache = nil
+	return errors.Join(errs...)
+}
+
+// New returns an empty container.
+func New() *Container {
+	return &Container{
+		providers: make(map[reflect.Type]*provider),
+		instances: instances{cache: make(map[reflect.Type]any)},
+	}
+}
+
+// Provide registers ctor as the constructor of T. The constructor resolves
+// its own dependencies from r. Registering T twice is an error.
+func Provide[T any](c *Container, lifetime Lifetime, ctor func(r *Resolver) (T, error)) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.providers[t]; ok {
+		return fmt.Errorf("di: %s already registered", t)
+	}
+	c.providers[t] = &provider{
+		lifetime: lifetime,
+		build:    func(r *Resolver) (any, error) { return ctor(r) },
+	}
+	return nil
+}
+
+// MustProvide is like Provide but panics on error.
+func MustProvide[T any](c *Container, lifetime Lifetime, ctor func(r *Resolver) (T, error)) {
+	if err := Provide(c, lifetime, ctor); err != nil {
+		panic(err)
+	}
+}
+
+// Value registers an existing value as the singleton instance of T.
+func Value[T any](c *Container, v T) error {
+	return Provide(c, Singleton, func(*Resolver) (T, error) { return v, nil })
+}
+
+// Close closes every singleton that implements io.Closer, in the reverse
+// order they were created. Resolving afterwards fails with ErrClosed.
+func (c *Container) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.close()
+}
+
+// Scope holds the scoped instances of one unit of work, such as a request.
+type Scope struct {
+	c *Container
+	instances
+}
+
+// NewScope returns a scope for resolving scoped instances.
+func (c *Container) NewScope() *Scope {
+	return &Scope{c: c, instances: instances{cache: make(map[reflect.Type]any)}}
+}
+
+// Close closes the scoped and transient instances created for the scope.
+func (s *Scope) Close() error {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	return s.close()
+}
+
+// Source is something instances can be resolved from: a *Container, a
+// *Scope or the *Resolver passed to a constructor.
+type Source interface {
+	resolver() *Resolver
+}
+
+func (c *Container) resolver() *Resolver { return &Resolver{c: c} }
+func (s *Scope) resolver() *Resolver     { return &Resolver{c: s.c, scope: s} }
+
+// Resolver is handed to constructors to resolve their dependencies. It
+// tracks the chain of types being built to detect cycles.
+type Resolver struct {
+	c      *Container
+	scope  *Scope
+	stack  []reflect.Type
+	locked bool
+}
+
+func (r *Resolver) resolver() *Resolver { return r }
+
+// Resolve returns the instance of T from src, building it and its
+// dependencies as needed.
+func Resolve[T any](src Source) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	v, err := src.resolver().resolve(t)
+	if err != nil {
+		return zero, err
+	}
+	// v is nil when an interface constructor returned a nil value.
+	tv, _ := v.(T)
+	return tv, nil
+}
+
+// MustResolve is like Resolve but panics on error.
+func MustResolve[T any](src Source) T {
+	v, err := Resolve[T](src)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (r *Resolver) resolve(t reflect.Type) (any, error) {
+	if !r.locked {
+		r.c.mu.Lock()
+		defer r.c.mu.Unlock()
+		r = &Resolver{c: r.c, scope: r.scope, locked: true}
+	}
+	for i, s := range r.stack {
+		if s == t {
+			path := append(append([]reflect.Type(nil), r.stack[i:]...), t)
+			return nil, &CycleError{Path: path}
+		}
+	}
+	p, ok := r.c.providers[t]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s%s", ErrNotRegistered, t, r.chain())
+	}
+
+	var owner *instances
+	switch p.lifetime {
+	case Singleton:
+		owner = &r.c.instances
+	case Scoped:
+		if r.scope == nil {
+			return nil, fmt.Errorf("di: scoped %s resolved outside a scope%s", t, r.chain())
+		}
+		owner = &r.scope.instances
+	case Transient:
+		owner = &r.c.instances
+		if r.scope != nil {
+			owner = &r.scope.instances
+		}
+	}
+	if owner.closed {
+		return nil, ErrClosed
+	}
+	if v, ok := owner.cache[t]; ok {
+		return v, nil
+	}
+
+	next := &Resolver{c: r.c, scope: r.scope, stack: append(r.stack[:len(r.stack):len(r.stack)], t), locked: true}
+	if p.lifetime == Singleton {
+		// A singleton outlives every scope, so it must not capture
+		// scoped dependencies.
+		next.scope = nil
+	}
+	v, err := p.build(next)
+	if err != nil {
+		var cycle *CycleError
+		if errors.As(err, &cycle) || errors.Is(err, ErrNotRegistered) || errors.Is(err, ErrClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("di: building %s%s: %w", t, r.chain(), err)
+	}
+	switch {
+	case p.lifetime != Transient:
+		owner.cache[t] = v
+		owner.track(v)
+	case r.scope != nil:
+		owner.track(v)
+	}
+	// Transients resolved from the container itself belong to the caller,
+	// who must close them.
+	return v, nil
+}
+
+// chain describes who asked for the type being resolved.
+func (r *Resolver) chain() string {
+	if len(r.stack) == 0 {
+		return ""
+	}
+	names := make([]string, len(r.stack))
+	for i, t := range r.stack {
+		names[i] = t.String()
+	}
+	return " (required by " + strings.Join(names, " -> ") + ")"
+}
@@ -0,0 +1,175 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type config struct{ dsn string }
+
+type db struct {
+	cfg    *config
+	closed *[]string
+}
+
+func (d *db) Close() error {
+	*d.closed = append(*d.closed, "db")
+	return nil
+}
+
+type repo struct {
+	db     *db
+	closed *[]string
+}
+
+func (r *repo) Close() error {
+	*r.closed = append(*r.closed, "repo")
+	return nil
+}
+
+type request struct{ id int }
+
+func newTestContainer(closed *[]string) *Container {
+	c := New()
+	MustProvide(c, Singleton, func(*Resolver) (*config, error) { return &config{dsn: "mem"}, nil })
+	MustProvide(c, Singleton, func(r *Resolver) (*db, error) {
+		cfg, err := Resolve[*config](r)
+		return &db{cfg: cfg, closed: closed}, err
+	})
+	MustProvide(c, Singleton, func(r *Resolver) (*repo, error) {
+		d, err := Resolve[*db](r)
+		return &repo{db: d, closed: closed}, err
+	})
+	n := 0
+	MustProvide(c, Scoped, func(*Resolver) (*request, error) {
+		n++
+		return &request{id: n}, nil
+	})
+	return c
+}
+
+func TestSingletonsAndCloseOrder(t *testing.T) {
+	var closed []string
+	c := newTestContainer(&closed)
+	r1 := MustResolve[*repo](c)
+	r2 := MustResolve[*repo](c)
+	if r1 != r2 || r1.db.cfg.dsn != "mem" {
+		t.Fatal("singleton resolved twice or wired wrongly")
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(closed, ","); got != "repo,db" {
+		t.Errorf("closed %s, want repo,db", got)
+	}
+	if _, err := Resolve[*repo](c); !errors.Is(err, ErrClosed) {
+		t.Errorf("Resolve after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestScopedAndTransient(t *testing.T) {
+	c := newTestContainer(new([]string))
+	if _, err := Resolve[*request](c); err == nil {
+		t.Error("scoped type resolved outside a scope")
+	}
+
+	s1, s2 := c.NewScope(), c.NewScope()
+	a, b := MustResolve[*request](s1), MustResolve[*request](s1)
+	if a != b {
+		t.Error("scoped type built twice in one scope")
+	}
+	if MustResolve[*request](s2) == a {
+		t.Error("scopes share a scoped instance")
+	}
+
+	var built int
+	var closed []string
+	MustProvide(c, Transient, func(*Resolver) (io.Closer, error) {
+		built++
+		return &db{closed: &closed}, nil
+	})
+	MustResolve[io.Closer](s1)
+	MustResolve[io.Closer](s1)
+	if built != 2 {
+		t.Errorf("transient built %d times, want 2", built)
+	}
+	if err := s1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 2 {
+		t.Errorf("scope closed %d transients, want 2", len(closed))
+	}
+	if _, err := Resolve[*request](s1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Resolve from closed scope = %v, want ErrClosed", err)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	type a struct{}
+	type b struct{}
+	c := New()
+	MustProvide(c, Singleton, func(r *Resolver) (*a, error) {
+		_, err := Resolve[*b](r)
+		return &a{}, err
+	})
+	MustProvide(c, Singleton, func(r *Resolver) (*b, error) {
+		_, err := Resolve[*a](r)
+		return &b{}, err
+	})
+	_, err := Resolve[*a](c)
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("Resolve = %v, want *CycleError", err)
+	}
+	if want := "di: dependency cycle: *di.a -> *di.b -> *di.a"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c := newTestContainer(new([]string))
+	if err := Provide(c, Singleton, func(*Resolver) (*config, error) { return nil, nil }); err == nil {
+		t.Error("duplicate Provide succeeded")
+	}
+
+	type missing struct{}
+	type needsMissing struct{}
+	MustProvide(c, Singleton, func(r *Resolver) (*needsMissing, error) {
+		_, err := Resolve[*missing](r)
+		return nil, err
+	})
+	_, err := Resolve[*needsMissing](c)
+	if !errors.Is(err, ErrNotRegistered) || !strings.Contains(err.Error(), "required by *di.needsMissing") {
+		t.Errorf("Resolve = %v, want ErrNotRegistered naming the dependent", err)
+	}
+
+	errBoom := errors.New("boom")
+	type broken struct{}
+	MustProvide(c, Singleton, func(*Resolver) (*broken, error) { return nil, errBoom })
+	if _, err := Resolve[*broken](c); !errors.Is(err, errBoom) {
+		t.Errorf("Resolve = %v, want errBoom", err)
+	}
+}
+
+func TestNilInterfaceValue(t *testing.T) {
+	c := New()
+	MustProvide(c, Singleton, func(*Resolver) (fmt.Stringer, error) { return nil, nil })
+	v, err := Resolve[fmt.Stringer](c)
+	if err != nil || v != nil {
+		t.Errorf("Resolve = %v, %v, want nil, nil", v, err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	c := New()
+	cfg := &config{dsn: "prod"}
+	if err := Value(c, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if MustResolve[*config](c) != cfg {
+		t.Error("Value did not register the given instance")
+	}
+}
@@ -0,0 +1,21 @@
+package factory
+
+type Ak47 struct {
+	Gun
+}
+
+func newAk47(opts ...GunOption) IGun {
+	return &Ak47{
+		Gun: newGun("AK47 gun", 4, opts...),
+	}
+}
+
+func init() {
+	Guns.MustRegister("ak47", func() IGun { return newAk47() })
+	GunConfigs.MustRegister("ak47", Spec[IGun]{
+		Schema: gunSchema("AK47 gun", 4),
+		Build: func(v Values) (IGun, error) {
+			return newAk47(gunOptions(v)...), nil
+		},
+	})
+}
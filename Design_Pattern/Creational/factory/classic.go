@@ -0,0 +1,32 @@
+package factory
+
+// classicFamily makes muskets with a matching holster and ammunition.
+type classicFamily struct{}
+
+var _ Family = classicFamily{}
+
+func (classicFamily) Name() string        { return "classic" }
+func (classicFamily) NewGun() IGun        { return newMusket() }
+func (classicFamily) NewHolster() Holster { return &classicHolster{} }
+func (classicFamily) NewAmmo() Ammo       { return &classicAmmo{rounds: 12} }
+
+type classicHolster struct{}
+
+func (h *classicHolster) Style() string { return "Leather sling" }
+
+func (h *classicHolster) Holds(g IGun) bool {
+	_, ok := g.(*Musket)
+	return ok
+}
+
+type classicAmmo struct {
+	rounds int
+}
+
+func (a *classicAmmo) Style() string   { return "Powder pouch" }
+func (a *classicAmmo) Caliber() string { return "musket ball" }
+func (a *classicAmmo) Rounds() int     { return a.rounds }
+
+func init() {
+	Families.MustRegister("classic", func() Family { return classicFamily{} })
+}
@@ -0,0 +1,411 @@
+package factory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParamType is the type of a configuration parameter.
+type ParamType int
+
+const (
+	String ParamType = iota
+	Int
+	Float
+	Bool
+	Duration
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Int:
+		return "integer"
+	case Float:
+		return "number"
+	case Bool:
+		return "boolean"
+	case Duration:
+		return "duration"
+	}
+	return fmt.Sprintf("ParamType(%d)", int(t))
+}
+
+// Param declares one parameter accepted by a configurable product. Default
+// is used when the parameter is absent and must already have the Go type
+// the parameter decodes to: string, int, float64, bool or time.Duration.
+// Check, if set, validates the decoded value.
+type Param struct {
+	Name     string
+	Type     ParamType
+	Required bool
+	Default  any
+	Doc      string
+	Check    func(v any) error
+}
+
+// Schema lists the parameters a product accepts.
+type Schema []Param
+
+// Spec tells a ConfigRegistry how to build one type of product from its
+// validated parameters.
+type Spec[T any] struct {
+	Schema Schema
+	Build  func(v Values) (T, error)
+}
+
+// Values holds the decoded parameters of one product, defaults included.
+type Values struct {
+	values map[string]any
+	set    map[string]bool
+}
+
+func (v Values) String(name string) string          { s, _ := v.values[name].(string); return s }
+func (v Values) Int(name string) int                { i, _ := v.values[name].(int); return i }
+func (v Values) Float(name string) float64          { f, _ := v.values[name].(float64); return f }
+func (v Values) Bool(name string) bool              { b, _ := v.values[name].(bool); return b }
+func (v Values) Duration(name string) time.Duration { d, _ := v.values[name].(time.Duration); return d }
+
+// Has reports whether the parameter has a value, given or defaulted.
+func (v Values) Has(name string) bool {
+	_, ok := v.values[name]
+	return ok
+}
+
+// IsSet reports whether the parameter was given in the configuration rather
+// than defaulted.
+func (v Values) IsSet(name string) bool {
+	return v.set[name]
+}
+
+// FieldError is a problem with the value at a JSON path such as $[1].power.
+type FieldError struct {
+	Path string
+	Msg  string
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Msg
+}
+
+// ConfigError collects every FieldError found in a configuration document.
+type ConfigError struct {
+	Errors []*FieldError
+}
+
+func (e *ConfigError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid configuration:\n  " + strings.Join(msgs, "\n  ")
+}
+
+// TypeKey is the JSON field that selects the registered product type.
+const TypeKey = "type"
+
+// ConfigRegistry builds products from JSON documents such as
+// {"type":"ak47","power":4}, where type selects a registered Spec and the
+// other fields are checked against its Schema.
+type ConfigRegistry[T any] struct {
+	specs *Registry[*Spec[T]]
+}
+
+// NewConfigRegistry returns an empty registry.
+func NewConfigRegistry[T any]() *ConfigRegistry[T] {
+	return &ConfigRegistry[T]{specs: NewRegistry[*Spec[T]]()}
+}
+
+// Register adds spec under name.
+func (r *ConfigRegistry[T]) Register(name string, spec Spec[T]) error {
+	for _, p := range spec.Schema {
+		if p.Name == TypeKey {
+			return fmt.Errorf("factory: %s: parameter name %q is reserved", name, TypeKey)
+		}
+		if p.Default != nil {
+			if _, err := convert(p.Type, p.Default); err != nil {
+				return fmt.Errorf("factory: %s: default for %q: %v", name, p.Name, err)
+			}
+		}
+	}
+	return r.specs.Register(name, func() *Spec[T] { return &spec })
+}
+
+// MustRegister is like Register but panics on error.
+func (r *ConfigRegistry[T]) MustRegister(name string, spec Spec[T]) {
+	if err := r.Register(name, spec); err != nil {
+		panic(err)
+	}
+}
+
+// Names returns the registered type names in sorted order.
+func (r *ConfigRegistry[T]) Names() []string {
+	return r.specs.Names()
+}
+
+// Schema returns the schema registered under name.
+func (r *ConfigRegistry[T]) Schema(name string) (Schema, bool) {
+	spec, err := r.specs.New(name)
+	if err != nil {
+		return nil, false
+	}
+	return spec.Schema, true
+}
+
+// Decode builds one product from a JSON object. Validation problems are
+// reported together in a *ConfigError.
+func (r *ConfigRegistry[T]) Decode(data []byte) (T, error) {
+	var zero T
+	var obj map[string]any
+	if err := unmarshal(data, &obj); err != nil {
+		return zero, err
+	}
+	var errs []*FieldError
+	v, ok := r.decode("$", obj, &errs)
+	if len(errs) > 0 || !ok {
+		return zero, &ConfigError{Errors: errs}
+	}
+	return v, nil
+}
+
+// DecodeList builds one product per element of a JSON array.
+func (r *ConfigRegistry[T]) DecodeList(data []byte) ([]T, error) {
+	// The elements are decoded one by one so that an element that is not
+	// an object is reported at its own path.
+	var list []json.RawMessage
+	if err := unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var errs []*FieldError
+	products := make([]T, 0, len(list))
+	for i, raw := range list {
+		path := fmt.Sprintf("$[%d]", i)
+		var elem any
+		if err := unmarshal(raw, &elem); err != nil {
+			return nil, err
+		}
+		obj, ok := elem.(map[string]any)
+		if !ok {
+			errs = append(errs, &FieldError{Path: path, Msg: "expected an object, got " + describe(elem)})
+			continue
+		}
+		if v, ok := r.decode(path, obj, &errs); ok {
+			products = append(products, v)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &ConfigError{Errors: errs}
+	}
+	return products, nil
+}
+
+func (r *ConfigRegistry[T]) decode(path string, obj map[string]any, errs *[]*FieldError) (T, bool) {
+	var zero T
+	fail := func(p, format string, args ...any) {
+		*errs = append(*errs, &FieldError{Path: p, Msg: fmt.Sprintf(format, args...)})
+	}
+	if obj == nil {
+		fail(path, "expected an object")
+		return zero, false
+	}
+
+	rawType, present := obj[TypeKey]
+	typ, ok := rawType.(string)
+	if !ok {
+		if present {
+			fail(path+"."+TypeKey, "expected a string, got %s", describe(rawType))
+		} else {
+			fail(path+"."+TypeKey, "required string field is missing")
+		}
+		return zero, false
+	}
+	spec, err := r.specs.New(typ)
+	if err != nil {
+		msg := fmt.Sprintf("unknown type %q", typ)
+		var unknown *UnknownNameError
+		if errors.As(err, &unknown) && len(unknown.Suggestions) > 0 {
+			msg += fmt.Sprintf(", did you mean %s?", quoteJoin(unknown.Suggestions))
+		}
+		fail(path+"."+TypeKey, "%s", msg)
+		return zero, false
+	}
+
+	known := make(map[string]bool, len(spec.Schema))
+	names := make([]string, 0, len(spec.Schema))
+	for _, p := range spec.Schema {
+		known[p.Name] = true
+		names = append(names, p.Name)
+	}
+	var unknownKeys []string
+	for key := range obj {
+		if key != TypeKey && !known[key] {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	sort.Strings(unknownKeys)
+	for _, key := range unknownKeys {
+		msg := "unknown parameter"
+		if s := Suggest(key, names); len(s) > 0 {
+			msg += fmt.Sprintf(", did you mean %s?", quoteJoin(s))
+		}
+		fail(path+"."+key, "%s", msg)
+	}
+
+	vals := Values{values: make(map[string]any), set: make(map[string]bool)}
+	before := len(*errs)
+	for _, p := range spec.Schema {
+		raw, present := obj[p.Name]
+		if !present {
+			switch {
+			case p.Required:
+				fail(path+"."+p.Name, "required %s parameter is missing", p.Type)
+			case p.Default != nil:
+				vals.values[p.Name], _ = convert(p.Type, p.Default)
+			}
+			continue
+		}
+		v, err := convert(p.Type, raw)
+		if err == nil && p.Check != nil {
+			err = p.Check(v)
+		}
+		if err != nil {
+			fail(path+"."+p.Name, "%v", err)
+			continue
+		}
+		vals.values[p.Name] = v
+		vals.set[p.Name] = true
+	}
+	if len(*errs) > before || len(unknownKeys) > 0 {
+		return zero, false
+	}
+
+	product, err := spec.Build(vals)
+	if err != nil {
+		fail(path, "%v", err)
+		return zero, false
+	}
+	return product, true
+}
+
+func unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			want := "an object"
+			if typeErr.Type.Kind() == reflect.Slice {
+				want = "an array of objects"
+			}
+			return &ConfigError{Errors: []*FieldError{{Path: "$", Msg: "expected " + want + ", got " + typeErr.Value}}}
+		}
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid configuration: unexpected data after the top-level value")
+	}
+	return nil
+}
+
+// convert turns a decoded JSON value, or a Go default, into the Go type of
+// a parameter.
+func convert(t ParamType, raw any) (any, error) {
+	switch t {
+	case String:
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+	case Int:
+		switch v := raw.(type) {
+		case int:
+			return v, nil
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return int(i), nil
+			}
+			return nil, fmt.Errorf("expected an integer, got %s", v)
+		}
+	case Float:
+		switch v := raw.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case json.Number:
+			return v.Float64()
+		}
+	case Bool:
+		if b, ok := raw.(bool); ok {
+			return b, nil
+		}
+	case Duration:
+		switch v := raw.(type) {
+		case time.Duration:
+			return v, nil
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("expected a duration such as \"1.5s\", got %q", v)
+			}
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("expected %s, got %s", article(t.String()), describe(raw))
+}
+
+func article(noun string) string {
+	if strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
+
+func describe(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case json.Number, int, float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// IntRange returns a Check that accepts integers between lo and hi
+// inclusive.
+func IntRange(lo, hi int) func(any) error {
+	return func(v any) error {
+		if i := v.(int); i < lo || i > hi {
+			return fmt.Errorf("must be between %d and %d, got %d", lo, hi, i)
+		}
+		return nil
+	}
+}
+
+// OneOf returns a Check that accepts only the given strings.
+func OneOf(values ...string) func(any) error {
+	return func(v any) error {
+		s := v.(string)
+		for _, allowed := range values {
+			if s == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s, got %q", quoteJoin(values), s)
+	}
+}
@@ -0,0 +1,160 @@
+package factory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGunFromConfig(t *testing.T) {
+	g, err := GunFromConfig([]byte(`{"type":"ak47","power":7}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Name() != "AK47 gun" || g.Power() != 7 {
+		t.Errorf("got %s with power %d, want AK47 gun with power 7", g.Name(), g.Power())
+	}
+
+	g, err = GunFromConfig([]byte(`{"type":"musket"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Power() != 1 {
+		t.Errorf("default power = %d, want 1", g.Power())
+	}
+}
+
+func TestConfigErrorPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want map[string]string
+	}{
+		{
+			name: "missing type",
+			doc:  `{"power":3}`,
+			want: map[string]string{"$.type": "required string field is missing"},
+		},
+		{
+			name: "type not a string",
+			doc:  `{"type":5}`,
+			want: map[string]string{"$.type": "expected a string, got a number"},
+		},
+		{
+			name: "unknown type",
+			doc:  `{"type":"muskat"}`,
+			want: map[string]string{"$.type": `unknown type "muskat", did you mean "musket"?`},
+		},
+		{
+			name: "bad values",
+			doc:  `{"type":"ak47","power":11,"nmae":"x"}`,
+			want: map[string]string{
+				"$.power": "must be between 1 and 10, got 11",
+				"$.nmae":  `unknown parameter, did you mean "name"?`,
+			},
+		},
+		{
+			name: "list element not an object",
+			doc:  `[{"type":"ak47"}, 3, null, {"type":"musket","power":0}]`,
+			want: map[string]string{
+				"$[1]":       "expected an object, got a number",
+				"$[2]":       "expected an object, got null",
+				"$[3].power": "must be between 1 and 10, got 0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if strings.HasPrefix(tt.doc, "[") {
+				_, err = GunConfigs.DecodeList([]byte(tt.doc))
+			} else {
+				_, err = GunFromConfig([]byte(tt.doc))
+			}
+			var cfgErr *ConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("error = %v, want *ConfigError", err)
+			}
+			got := make(map[string]string)
+			for _, fe := range cfgErr.Errors {
+				got[fe.Path] = fe.Msg
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			for path, msg := range tt.want {
+				if got[path] != msg {
+					t.Errorf("%s: %q, want %q", path, got[path], msg)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeList(t *testing.T) {
+	guns, err := GunConfigs.DecodeList([]byte(`[{"type":"ak47"},{"type":"musket","name":"Old"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(guns) != 2 || guns[1].Name() != "Old" {
+		t.Errorf("guns = %v", guns)
+	}
+
+	_, err = GunConfigs.DecodeList([]byte(`[{"type":"ak47"},{"type":"musket","power":"high"}]`))
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || len(cfgErr.Errors) != 1 || cfgErr.Errors[0].Path != "$[1].power" {
+		t.Errorf("error = %v, want one error at $[1].power", err)
+	}
+}
+
+func TestConfigRejectsMalformedDocuments(t *testing.T) {
+	for _, doc := range []string{`[1]`, `{"type":"ak47"} {}`, `{"type":`} {
+		if _, err := GunFromConfig([]byte(doc)); err == nil {
+			t.Errorf("GunFromConfig(%s) succeeded", doc)
+		}
+	}
+}
+
+func TestConfigRegistryParamTypes(t *testing.T) {
+	type timer struct {
+		every time.Duration
+		ratio float64
+		on    bool
+		mode  string
+	}
+	r := NewConfigRegistry[timer]()
+	r.MustRegister("timer", Spec[timer]{
+		Schema: Schema{
+			{Name: "every", Type: Duration, Required: true},
+			{Name: "ratio", Type: Float, Default: 1},
+			{Name: "on", Type: Bool, Default: true},
+			{Name: "mode", Type: String, Default: "fast", Check: OneOf("fast", "slow")},
+		},
+		Build: func(v Values) (timer, error) {
+			return timer{v.Duration("every"), v.Float("ratio"), v.Bool("on"), v.String("mode")}, nil
+		},
+	})
+
+	got, err := r.Decode([]byte(`{"type":"timer","every":"1.5s","ratio":0.5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := timer{1500 * time.Millisecond, 0.5, true, "fast"}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	_, err = r.Decode([]byte(`{"type":"timer","mode":"medium"}`))
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || len(cfgErr.Errors) != 2 {
+		t.Errorf("error = %v, want missing every and bad mode", err)
+	}
+
+	if err := r.Register("bad", Spec[timer]{Schema: Schema{{Name: "n", Type: Int, Default: "one"}}}); err == nil {
+		t.Error("Register accepted a default of the wrong type")
+	}
+	if err := r.Register("bad", Spec[timer]{Schema: Schema{{Name: TypeKey, Type: String}}}); err == nil {
+		t.Error("Register accepted a parameter named type")
+	}
+}
@@ -0,0 +1,48 @@
+package factory
+
+// Holster carries a gun of its own family.
+type Holster interface {
+	Style() string
+	Holds(g IGun) bool
+}
+
+// Ammo is the ammunition of one family.
+type Ammo interface {
+	Style() string
+	Caliber() string
+	Rounds() int
+}
+
+// Family is an abstract factory for a set of products that belong
+// together. Because every product has its own method, a family that
+// forgets one does not compile.
+type Family interface {
+	Name() string
+	NewGun() IGun
+	NewHolster() Holster
+	NewAmmo() Ammo
+}
+
+// Families holds every registered product family.
+var Families = NewRegistry[Family]()
+
+// Kit is a matching set of products from one family.
+type Kit struct {
+	Gun     IGun
+	Holster Holster
+	Ammo    Ammo
+}
+
+// NewKit creates one of each product of f.
+func NewKit(f Family) Kit {
+	return Kit{Gun: f.NewGun(), Holster: f.NewHolster(), Ammo: f.NewAmmo()}
+}
+
+// KitFor creates a kit from the family registered under name.
+func KitFor(name string) (Kit, error) {
+	f, err := Families.New(name)
+	if err != nil {
+		return Kit{}, err
+	}
+	return NewKit(f), nil
+}
@@ -0,0 +1,42 @@
+package factory
+
+import "testing"
+
+func TestFamiliesAreConsistent(t *testing.T) {
+	for _, name := range Families.Names() {
+		f, err := Families.New(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name() != name {
+			t.Errorf("family registered as %q is named %q", name, f.Name())
+		}
+		kit := NewKit(f)
+		if !kit.Holster.Holds(kit.Gun) {
+			t.Errorf("%s holster does not hold the %s gun", name, name)
+		}
+		if kit.Ammo.Rounds() <= 0 || kit.Ammo.Caliber() == "" {
+			t.Errorf("%s ammo = %+v", name, kit.Ammo)
+		}
+	}
+}
+
+func TestHolstersRejectOtherFamilies(t *testing.T) {
+	modern, err := KitFor("modern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	classic, err := KitFor("classic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modern.Holster.Holds(classic.Gun) || classic.Holster.Holds(modern.Gun) {
+		t.Error("a holster holds a gun of another family")
+	}
+}
+
+func TestKitForUnknownFamily(t *testing.T) {
+	if _, err := KitFor("futuristic"); err == nil {
+		t.Error("KitFor(futuristic) succeeded")
+	}
+}
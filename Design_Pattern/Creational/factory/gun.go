@@ -0,0 +1,77 @@
+package factory
+
+// IGun is the product interface.
+type IGun interface {
+	Name() string
+	Power() int
+}
+
+// Gun is the concrete product embedded by every gun.
+type Gun struct {
+	name  string
+	power int
+}
+
+func (g *Gun) Name() string {
+	return g.name
+}
+
+func (g *Gun) Power() int {
+	return g.power
+}
+
+// GunOption changes a gun as it is created.
+type GunOption func(*Gun)
+
+// WithName overrides the name of the gun.
+func WithName(name string) GunOption {
+	return func(g *Gun) {
+		g.name = name
+	}
+}
+
+// WithPower overrides the power of the gun.
+func WithPower(power int) GunOption {
+	return func(g *Gun) {
+		g.power = power
+	}
+}
+
+func newGun(name string, power int, opts ...GunOption) Gun {
+	g := Gun{name: name, power: power}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	return g
+}
+
+// Guns holds the constructor of every gun. Each gun registers itself from
+// its own file, so adding a gun does not touch the factory.
+var Guns = NewRegistry[IGun]()
+
+// GunConfigs builds guns from JSON configuration.
+var GunConfigs = NewConfigRegistry[IGun]()
+
+// GetGun creates the gun registered under gunType.
+func GetGun(gunType string) (IGun, error) {
+	return Guns.New(gunType)
+}
+
+// GunFromConfig creates a gun from a JSON object such as
+// {"type":"ak47","power":4}.
+func GunFromConfig(data []byte) (IGun, error) {
+	return GunConfigs.Decode(data)
+}
+
+// gunSchema is the schema shared by the guns in this package.
+func gunSchema(name string, power int) Schema {
+	return Schema{
+		{Name: "name", Type: String, Default: name, Doc: "display name"},
+		{Name: "power", Type: Int, Default: power, Check: IntRange(1, 10), Doc: "damage from 1 to 10"},
+	}
+}
+
+// gunOptions turns validated configuration into options.
+func gunOptions(v Values) []GunOption {
+	return []GunOption{WithName(v.String("name")), WithPower(v.Int("power"))}
+}
@@ -0,0 +1,32 @@
+package factory
+
+// modernFamily makes AK47s with a matching holster and ammunition.
+type modernFamily struct{}
+
+var _ Family = modernFamily{}
+
+func (modernFamily) Name() string        { return "modern" }
+func (modernFamily) NewGun() IGun        { return newAk47() }
+func (modernFamily) NewHolster() Holster { return &modernHolster{} }
+func (modernFamily) NewAmmo() Ammo       { return &modernAmmo{rounds: 30} }
+
+type modernHolster struct{}
+
+func (h *modernHolster) Style() string { return "Tactical nylon holster" }
+
+func (h *modernHolster) Holds(g IGun) bool {
+	_, ok := g.(*Ak47)
+	return ok
+}
+
+type modernAmmo struct {
+	rounds int
+}
+
+func (a *modernAmmo) Style() string   { return "Magazine" }
+func (a *modernAmmo) Caliber() string { return "7.62x39mm" }
+func (a *modernAmmo) Rounds() int     { return a.rounds }
+
+func init() {
+	Families.MustRegister("modern", func() Family { return modernFamily{} })
+}
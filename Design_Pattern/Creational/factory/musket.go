@@ -0,0 +1,21 @@
+package factory
+
+type Musket struct {
+	Gun
+}
+
+func newMusket(opts ...GunOption) IGun {
+	return &Musket{
+		Gun: newGun("Musket gun", 1, opts...),
+	}
+}
+
+func init() {
+	Guns.MustRegister("musket", func() IGun { return newMusket() })
+	GunConfigs.MustRegister("musket", Spec[IGun]{
+		Schema: gunSchema("Musket gun", 1),
+		Build: func(v Values) (IGun, error) {
+			return newMusket(gunOptions(v)...), nil
+		},
+	})
+}
@@ -0,0 +1,153 @@
+// Package factory provides a registry based factory method. Concrete
+// products register a constructor under a name, usually from an init
+// function, and clients create them by name.
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrDuplicate is returned when a name is registered twice.
+var ErrDuplicate = errors.New("factory: name already registered")
+
+// UnknownNameError is returned when no constructor is registered under a
+// name. Suggestions holds registered names close to it.
+type UnknownNameError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownNameError) Error() string {
+	msg := fmt.Sprintf("factory: unknown name %q", e.Name)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", quoteJoin(e.Suggestions))
+	}
+	return msg
+}
+
+// Constructor creates a new product.
+type Constructor[T any] func() T
+
+// Registry maps names to constructors of T. It is safe for concurrent use.
+type Registry[T any] struct {
+	mu    sync.RWMutex
+	ctors map[string]Constructor[T]
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry[T any]() *Registry[T] {
+	return &Registry[T]{ctors: make(map[string]Constructor[T])}
+}
+
+// Register adds ctor under name.
+func (r *Registry[T]) Register(name string, ctor Constructor[T]) error {
+	if name == "" || ctor == nil {
+		return errors.New("factory: name and constructor must be set")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ctors[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicate, name)
+	}
+	r.ctors[name] = ctor
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is meant to be
+// called from init functions, where a duplicate name is a programming error.
+func (r *Registry[T]) MustRegister(name string, ctor Constructor[T]) {
+	if err := r.Register(name, ctor); err != nil {
+		panic(err)
+	}
+}
+
+// New creates the product registered under name. If there is none, the
+// error is an *UnknownNameError.
+func (r *Registry[T]) New(name string) (T, error) {
+	r.mu.RLock()
+	ctor, ok := r.ctors[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, &UnknownNameError{Name: name, Suggestions: Suggest(name, r.Names())}
+	}
+	return ctor(), nil
+}
+
+// Has reports whether a constructor is registered under name.
+func (r *Registry[T]) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.ctors[name]
+	return ok
+}
+
+// Names returns the registered names in sorted order.
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.ctors))
+	for name := range r.ctors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Suggest returns up to three candidates within a small edit distance of
+// name, closest first. Case is ignored.
+func Suggest(name string, candidates []string) []string {
+	type match struct {
+		name string
+		dist int
+	}
+	lower := strings.ToLower(name)
+	limit := max(2, len(name)/3)
+	var matches []match
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		d := levenshtein(lower, lc)
+		if d <= limit || (len(lower) > 1 && strings.HasPrefix(lc, lower)) {
+			matches = append(matches, match{c, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].dist < matches[j].dist })
+	var names []string
+	for i := 0; i < len(matches) && i < 3; i++ {
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func quoteJoin(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, " or ")
+}
@@ -0,0 +1,83 @@
+package factory
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry[string]()
+	r.MustRegister("beta", func() string { return "b" })
+	r.MustRegister("alpha", func() string { return "a" })
+
+	if err := r.Register("alpha", func() string { return "again" }); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate Register = %v, want ErrDuplicate", err)
+	}
+	if err := r.Register("", func() string { return "" }); err == nil {
+		t.Error("Register accepted an empty name")
+	}
+	if err := r.Register("gamma", nil); err == nil {
+		t.Error("Register accepted a nil constructor")
+	}
+
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"alpha", "beta"}) {
+		t.Errorf("Names = %v, want [alpha beta]", got)
+	}
+	if !r.Has("alpha") || r.Has("gamma") {
+		t.Error("Has reports the wrong names")
+	}
+	if v, err := r.New("alpha"); err != nil || v != "a" {
+		t.Errorf(`New("alpha") = %q, %v`, v, err)
+	}
+}
+
+func TestRegistryUnknownName(t *testing.T) {
+	_, err := GetGun("ak74")
+	var unknown *UnknownNameError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("GetGun = %v, want *UnknownNameError", err)
+	}
+	if unknown.Name != "ak74" || len(unknown.Suggestions) == 0 || unknown.Suggestions[0] != "ak47" {
+		t.Errorf("error = %+v, want a suggestion of ak47", unknown)
+	}
+	if !strings.Contains(err.Error(), `did you mean "ak47"`) {
+		t.Errorf("message %q has no suggestion", err)
+	}
+
+	_, err = GetGun("bazooka")
+	if !errors.As(err, &unknown) || len(unknown.Suggestions) != 0 {
+		t.Errorf("GetGun(bazooka) = %v, want no suggestions", err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"ak47", "musket", "mustang", "rifle"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"MUSKET", []string{"musket"}},
+		{"muskit", []string{"musket"}},
+		{"mus", []string{"musket", "mustang"}},
+		{"cannon", nil},
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.name, names); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetGun(t *testing.T) {
+	for _, name := range []string{"ak47", "musket"} {
+		g, err := GetGun(name)
+		if err != nil {
+			t.Fatalf("GetGun(%q): %v", name, err)
+		}
+		if g.Name() == "" || g.Power() <= 0 {
+			t.Errorf("GetGun(%q) = %+v", name, g)
+		}
+	}
+}
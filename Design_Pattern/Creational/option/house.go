@@ -0,0 +1,83 @@
+package option
+
+import (
+	"errors"
+	"fmt"
+)
+
+// House is the example product from optional.md.
+type House struct {
+	Material     string
+	HasFireplace bool
+	Floors       int
+}
+
+var houseSpec = Spec[House]{
+	Defaults: func(h *House) {
+		h.Material = "wood"
+		h.HasFireplace = true
+		h.Floors = 2
+	},
+	Validate: func(h *House) error {
+		if h.Material == "straw" && h.Floors > 1 {
+			return errors.New("a straw house can only have one floor")
+		}
+		return nil
+	},
+}
+
+// HouseOptions declares the parameters of a House, so they can also be
+// set from flags, environment variables and config files.
+var HouseOptions = NewSchema(houseSpec)
+
+var (
+	materialParam = String(HouseOptions, "material", "building material",
+		func(h *House) *string { return &h.Material }).
+		Check(func(m string) error {
+			if m == "" {
+				return errors.New("material must not be empty")
+			}
+			return nil
+		})
+	fireplaceParam = Bool(HouseOptions, "fireplace", "whether the house has a fireplace",
+		func(h *House) *bool { return &h.HasFireplace })
+	floorsParam = Int(HouseOptions, "floors", "number of floors",
+		func(h *House) *int { return &h.Floors }).
+		Check(func(n int) error {
+			if n < 1 {
+				return fmt.Errorf("floors must be at least 1, got %d", n)
+			}
+			return nil
+		})
+)
+
+// NewHouse returns a wooden two floor house with a fireplace, changed by
+// opts.
+func NewHouse(opts ...Option[House]) (*House, error) {
+	return houseSpec.New(opts...)
+}
+
+// WithMaterial sets the building material.
+func WithMaterial(material string) Option[House] {
+	return materialParam.Option(material)
+}
+
+// WithConcrete builds the house from concrete.
+func WithConcrete() Option[House] {
+	return materialParam.Option("concrete")
+}
+
+// WithoutFireplace leaves out the fireplace.
+func WithoutFireplace() Option[House] {
+	return fireplaceParam.Option(false)
+}
+
+// WithFloors sets the number of floors.
+func WithFloors(floors int) Option[House] {
+	return floorsParam.Option(floors)
+}
+
+// Bungalow is a group of options for a single floor concrete house.
+func Bungalow() Option[House] {
+	return Group("Bungalow", WithConcrete(), WithFloors(1))
+}
@@ -0,0 +1,119 @@
+// Package option is a generic toolkit for the functional options pattern
+// described in optional.md. Constructors declare their defaults and
+// validation once in a Spec and take options built with New, NewE and
+// Group.
+package option
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Option changes a value of type T as it is constructed. Every option has
+// a name, used in error messages and to report which options were set.
+type Option[T any] struct {
+	name  string
+	apply func(*T) error
+	group []Option[T]
+}
+
+// New returns an option that cannot fail.
+func New[T any](name string, fn func(*T)) Option[T] {
+	return Option[T]{name: name, apply: func(t *T) error {
+		fn(t)
+		return nil
+	}}
+}
+
+// NewE returns an option that can reject its argument.
+func NewE[T any](name string, fn func(*T) error) Option[T] {
+	return Option[T]{name: name, apply: fn}
+}
+
+// Group bundles several options into one, applied in order. The options of
+// the group are reported as set individually.
+func Group[T any](name string, opts ...Option[T]) Option[T] {
+	return Option[T]{name: name, group: opts}
+}
+
+// Name returns the name of the option.
+func (o Option[T]) Name() string {
+	return o.name
+}
+
+// Set records the names of the options that were applied explicitly.
+type Set map[string]bool
+
+// Has reports whether the named option was applied.
+func (s Set) Has(name string) bool {
+	return s[name]
+}
+
+// Names returns the applied option names in sorted order.
+func (s Set) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply applies opts to t in order. Every failing option is reported, not
+// only the first. It returns the names of the options that were applied.
+func Apply[T any](t *T, opts ...Option[T]) (Set, error) {
+	set := make(Set)
+	var errs []error
+	apply(t, opts, set, &errs)
+	return set, errors.Join(errs...)
+}
+
+func apply[T any](t *T, opts []Option[T], set Set, errs *[]error) {
+	for _, o := range opts {
+		if o.apply == nil {
+			before := len(*errs)
+			apply(t, o.group, set, errs)
+			if len(*errs) == before {
+				set[o.name] = true
+			}
+			continue
+		}
+		if err := o.apply(t); err != nil {
+			*errs = append(*errs, fmt.Errorf("option %s: %w", o.name, err))
+			continue
+		}
+		set[o.name] = true
+	}
+}
+
+// Spec describes how to construct a T: its defaults, applied before the
+// options, and its validation, run after them.
+type Spec[T any] struct {
+	Defaults func(*T)
+	Validate func(*T) error
+}
+
+// New returns a T with the defaults and opts applied and validated.
+func (s Spec[T]) New(opts ...Option[T]) (*T, error) {
+	t, _, err := s.NewWithSet(opts...)
+	return t, err
+}
+
+// NewWithSet is like New but also returns which options were set.
+func (s Spec[T]) NewWithSet(opts ...Option[T]) (*T, Set, error) {
+	t := new(T)
+	if s.Defaults != nil {
+		s.Defaults(t)
+	}
+	set, err := Apply(t, opts...)
+	if err != nil {
+		return nil, set, err
+	}
+	if s.Validate != nil {
+		if err := s.Validate(t); err != nil {
+			return nil, set, err
+		}
+	}
+	return t, set, nil
+}
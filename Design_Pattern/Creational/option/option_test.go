@@ -0,0 +1,90 @@
+package option
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewHouse(t *testing.T) {
+	h, err := NewHouse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (House{Material: "wood", HasFireplace: true, Floors: 2}); *h != want {
+		t.Errorf("default house = %+v, want %+v", *h, want)
+	}
+
+	h, err = NewHouse(WithConcrete(), WithoutFireplace(), WithFloors(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (House{Material: "concrete", HasFireplace: false, Floors: 3}); *h != want {
+		t.Errorf("house = %+v, want %+v", *h, want)
+	}
+}
+
+func TestApplyReportsEveryError(t *testing.T) {
+	_, err := NewHouse(WithMaterial(""), WithFloors(0))
+	if err == nil {
+		t.Fatal("NewHouse succeeded with bad options")
+	}
+	for _, want := range []string{"option material", "option floors"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestValidateRunsAfterOptions(t *testing.T) {
+	if _, err := NewHouse(WithMaterial("straw")); err == nil {
+		t.Error("a two floor straw house was accepted")
+	}
+	if _, err := NewHouse(WithMaterial("straw"), WithFloors(1)); err != nil {
+		t.Errorf("one floor straw house: %v", err)
+	}
+}
+
+func TestGroupSet(t *testing.T) {
+	h, set, err := houseSpec.NewWithSet(Bungalow(), WithoutFireplace())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Material != "concrete" || h.Floors != 1 {
+		t.Errorf("bungalow = %+v", *h)
+	}
+	want := []string{"Bungalow", "fireplace", "floors", "material"}
+	if got := set.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("set = %v, want %v", got, want)
+	}
+
+	bad := Group("Bad", WithConcrete(), WithFloors(0))
+	_, set, err = houseSpec.NewWithSet(bad)
+	if err == nil {
+		t.Fatal("group with a failing option succeeded")
+	}
+	if set.Has("Bad") || !set.Has("material") {
+		t.Errorf("set = %v, want material but not Bad", set.Names())
+	}
+}
+
+func TestNewE(t *testing.T) {
+	errOdd := errors.New("odd")
+	even := func(n int) Option[int] {
+		return NewE("even", func(v *int) error {
+			if n%2 != 0 {
+				return errOdd
+			}
+			*v = n
+			return nil
+		})
+	}
+	var v int
+	if _, err := Apply(&v, even(4)); err != nil || v != 4 {
+		t.Errorf("Apply(even(4)) = %d, %v", v, err)
+	}
+	if _, err := Apply(&v, even(3)); !errors.Is(err, errOdd) || v != 4 {
+		t.Errorf("Apply(even(3)) = %d, %v, want unchanged and errOdd", v, err)
+	}
+}
@@ -0,0 +1,375 @@
+package option
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Source is where the resolved value of a parameter came from.
+type Source string
+
+// Sources in increasing order of precedence.
+const (
+	FromDefault Source = "default"
+	FromFile    Source = "file"
+	FromEnv     Source = "env"
+	FromFlag    Source = "flag"
+	FromCode    Source = "code"
+)
+
+// binding is the type-erased view of a Param used by Schema.
+type binding[T any] interface {
+	Name() string
+	Info() ParamInfo
+	setString(t *T, s string) error
+	format(t *T) string
+}
+
+// ParamInfo describes a declared parameter.
+type ParamInfo struct {
+	Name    string
+	Type    string
+	Usage   string
+	Default string
+}
+
+// Param is a named, discoverable option. One declaration gives an Option
+// for code and a binding for flags, environment variables and config
+// files.
+type Param[T, V any] struct {
+	schema *Schema[T]
+	name   string
+	typ    string
+	usage  string
+	field  func(*T) *V
+	parse  func(string) (V, error)
+	check  func(V) error
+}
+
+// Option returns an option, named after the parameter, that sets it to v.
+func (p *Param[T, V]) Option(v V) Option[T] {
+	return NewE(p.name, func(t *T) error {
+		if p.check != nil {
+			if err := p.check(v); err != nil {
+				return err
+			}
+		}
+		*p.field(t) = v
+		return nil
+	})
+}
+
+// Check adds a validation of the parameter's value and returns p.
+func (p *Param[T, V]) Check(check func(V) error) *Param[T, V] {
+	p.check = check
+	return p
+}
+
+// Name returns the name of the parameter.
+func (p *Param[T, V]) Name() string {
+	return p.name
+}
+
+func (p *Param[T, V]) Info() ParamInfo {
+	var t T
+	if p.schema.spec.Defaults != nil {
+		p.schema.spec.Defaults(&t)
+	}
+	return ParamInfo{Name: p.name, Type: p.typ, Usage: p.usage, Default: p.format(&t)}
+}
+
+func (p *Param[T, V]) setString(t *T, s string) error {
+	v, err := p.parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", p.typ, s)
+	}
+	return p.Option(v).apply(t)
+}
+
+func (p *Param[T, V]) format(t *T) string {
+	return fmt.Sprint(*p.field(t))
+}
+
+// Schema is the list of parameters of a type T, together with the Spec
+// used to construct it.
+type Schema[T any] struct {
+	spec   Spec[T]
+	params []binding[T]
+	byName map[string]binding[T]
+}
+
+// NewSchema returns a schema constructing T with spec.
+func NewSchema[T any](spec Spec[T]) *Schema[T] {
+	return &Schema[T]{spec: spec, byName: make(map[string]binding[T])}
+}
+
+func declare[T, V any](s *Schema[T], p *Param[T, V]) *Param[T, V] {
+	if _, ok := s.byName[p.name]; ok {
+		panic(fmt.Sprintf("option: parameter %q declared twice", p.name))
+	}
+	p.schema = s
+	s.params = append(s.params, p)
+	s.byName[p.name] = p
+	return p
+}
+
+// String declares a string parameter stored in the field returned by field.
+func String[T any](s *Schema[T], name, usage string, field func(*T) *string) *Param[T, string] {
+	return declare(s, &Param[T, string]{name: name, typ: "string", usage: usage, field: field,
+		parse: func(v string) (string, error) { return v, nil }})
+}
+
+// Int declares an integer parameter.
+func Int[T any](s *Schema[T], name, usage string, field func(*T) *int) *Param[T, int] {
+	return declare(s, &Param[T, int]{name: name, typ: "int", usage: usage, field: field, parse: strconv.Atoi})
+}
+
+// Float declares a floating point parameter.
+func Float[T any](s *Schema[T], name, usage string, field func(*T) *float64) *Param[T, float64] {
+	return declare(s, &Param[T, float64]{name: name, typ: "float", usage: usage, field: field,
+		parse: func(v string) (float64, error) { return strconv.ParseFloat(v, 64) }})
+}
+
+// Bool declares a boolean parameter. As a flag it can be given without a
+// value to mean true.
+func Bool[T any](s *Schema[T], name, usage string, field func(*T) *bool) *Param[T, bool] {
+	return declare(s, &Param[T, bool]{name: name, typ: "bool", usage: usage, field: field, parse: strconv.ParseBool})
+}
+
+// Duration declares a duration parameter, written like "1m30s".
+func Duration[T any](s *Schema[T], name, usage string, field func(*T) *time.Duration) *Param[T, time.Duration] {
+	return declare(s, &Param[T, time.Duration]{name: name, typ: "duration", usage: usage, field: field, parse: time.ParseDuration})
+}
+
+// Params describes every declared parameter in declaration order.
+func (s *Schema[T]) Params() []ParamInfo {
+	infos := make([]ParamInfo, len(s.params))
+	for i, p := range s.params {
+		infos[i] = p.Info()
+	}
+	return infos
+}
+
+// Sources says where Load looks for values.
+type Sources struct {
+	// File is the path of a JSON config file whose keys are parameter
+	// names. It is skipped when empty.
+	File string
+	// EnvPrefix is prepended to the upper-cased parameter name, with
+	// dashes turned into underscores, to get the environment variable.
+	// The environment is not read when it is empty.
+	EnvPrefix string
+	// LookupEnv defaults to os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+	// Flags is the flag set parameters are registered on. Parameters it
+	// already defines a flag for are not registered again. Args are parsed
+	// with a copy of its flags, so only the flags in Args count and the
+	// same set can be passed to Load again. Flags are not read when it is
+	// nil.
+	Flags *flag.FlagSet
+	Args  []string
+}
+
+// EnvName returns the environment variable read for a parameter.
+func EnvName(prefix, name string) string {
+	return prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// Resolved is one parameter of a loaded value and where it came from.
+type Resolved struct {
+	Name   string
+	Value  string
+	Source Source
+}
+
+// Resolution lists every parameter of a loaded value.
+type Resolution []Resolved
+
+// Print writes the resolution as an aligned table.
+func (r Resolution) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tSOURCE")
+	for _, p := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, p.Value, p.Source)
+	}
+	return tw.Flush()
+}
+
+// Load builds a T from its defaults, then the config file, the environment,
+// the flags and finally opts, each overriding the ones before. It returns
+// the value together with where each parameter came from.
+func (s *Schema[T]) Load(src Sources, opts ...Option[T]) (*T, Resolution, error) {
+	t := new(T)
+	if s.spec.Defaults != nil {
+		s.spec.Defaults(t)
+	}
+	sources := make(map[string]Source, len(s.params))
+	var errs []error
+	set := func(p binding[T], raw string, from Source, where string) {
+		if err := p.setString(t, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", where, err))
+			return
+		}
+		sources[p.Name()] = from
+	}
+
+	if src.File != "" {
+		values, err := readFile(src.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p, ok := s.byName[k]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown parameter %q", src.File, k))
+				continue
+			}
+			set(p, values[k], FromFile, src.File+": "+k)
+		}
+	}
+
+	if src.EnvPrefix != "" {
+		lookup := src.LookupEnv
+		if lookup == nil {
+			lookup = os.LookupEnv
+		}
+		for _, p := range s.params {
+			env := EnvName(src.EnvPrefix, p.Name())
+			if v, ok := lookup(env); ok {
+				set(p, v, FromEnv, "$"+env)
+			}
+		}
+	}
+
+	if src.Flags != nil {
+		s.registerFlags(src.Flags)
+		fs := parseSet(src.Flags)
+		if err := fs.Parse(src.Args); err != nil {
+			return nil, nil, err
+		}
+		fs.Visit(func(f *flag.Flag) {
+			if p, ok := s.byName[f.Name]; ok {
+				set(p, f.Value.String(), FromFlag, "-"+f.Name)
+			}
+		})
+	}
+
+	applied, err := Apply(t, opts...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for name := range applied {
+		if _, ok := s.byName[name]; ok {
+			sources[name] = FromCode
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	if s.spec.Validate != nil {
+		if err := s.spec.Validate(t); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	res := make(Resolution, len(s.params))
+	for i, p := range s.params {
+		name := p.Name()
+		from, ok := sources[name]
+		if !ok {
+			from = FromDefault
+		}
+		res[i] = Resolved{Name: name, Value: p.format(t), Source: from}
+	}
+	return t, res, nil
+}
+
+// flagValue records the raw text of a flag so Load can apply it in
+// precedence order.
+type flagValue struct {
+	raw    *string
+	isBool bool
+}
+
+func (f flagValue) String() string {
+	if f.raw == nil {
+		return ""
+	}
+	return *f.raw
+}
+
+func (f flagValue) Set(s string) error {
+	*f.raw = s
+	return nil
+}
+
+func (f flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// registerFlags defines a flag for every parameter that fs does not define
+// yet, so that Load can be called again with the same flag set and a flag
+// the program defined itself is read as it is.
+func (s *Schema[T]) registerFlags(fs *flag.FlagSet) {
+	for _, p := range s.params {
+		if fs.Lookup(p.Name()) != nil {
+			continue
+		}
+		info := p.Info()
+		fs.Var(flagValue{raw: new(string), isBool: info.Type == "bool"}, info.Name, fmt.Sprintf("%s (default %s)", info.Usage, info.Default))
+	}
+}
+
+// parseSet returns a new flag set with the flags of fs. Flags set in an
+// earlier Parse of fs are not remembered by it, but the values are shared,
+// so the program's own flags are still set.
+func parseSet(fs *flag.FlagSet) *flag.FlagSet {
+	c := flag.NewFlagSet(fs.Name(), fs.ErrorHandling())
+	c.SetOutput(fs.Output())
+	if fs.Usage != nil {
+		c.Usage = fs.Usage
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		c.Var(f.Value, f.Name, f.Usage)
+	})
+	return c
+}
+
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		switch v.(type) {
+		case string, json.Number, bool:
+			out[k] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("%s: %s must be a string, number or boolean", path, k)
+		}
+	}
+	return out, nil
+}
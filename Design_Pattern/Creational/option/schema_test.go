@@ -0,0 +1,149 @@
+package option
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHouseParams(t *testing.T) {
+	want := []ParamInfo{
+		{Name: "material", Type: "string", Usage: "building material", Default: "wood"},
+		{Name: "fireplace", Type: "bool", Usage: "whether the house has a fireplace", Default: "true"},
+		{Name: "floors", Type: "int", Usage: "number of floors", Default: "2"},
+	}
+	got := HouseOptions.Params()
+	if len(got) != len(want) {
+		t.Fatalf("Params = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Params[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "house.json")
+	if err := os.WriteFile(file, []byte(`{"material":"brick","floors":3,"fireplace":false}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"HOUSE_FLOORS": "4", "HOUSE_MATERIAL": "stone"}
+	fs := flag.NewFlagSet("house", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	h, res, err := HouseOptions.Load(Sources{
+		File:      file,
+		EnvPrefix: "HOUSE_",
+		LookupEnv: func(k string) (string, bool) { v, ok := env[k]; return v, ok },
+		Flags:     fs,
+		Args:      []string{"-floors", "5"},
+	}, WithConcrete())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (House{Material: "concrete", HasFireplace: false, Floors: 5}); *h != want {
+		t.Errorf("house = %+v, want %+v", *h, want)
+	}
+	wantSources := map[string]Source{"material": FromCode, "fireplace": FromFile, "floors": FromFlag}
+	for _, r := range res {
+		if r.Source != wantSources[r.Name] {
+			t.Errorf("%s from %s, want %s", r.Name, r.Source, wantSources[r.Name])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := res.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "floors") || !strings.Contains(buf.String(), "flag") {
+		t.Errorf("Print output:\n%s", buf.String())
+	}
+}
+
+func TestLoadDefaultsAndBoolFlag(t *testing.T) {
+	fs := flag.NewFlagSet("house", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	h, res, err := HouseOptions.Load(Sources{Flags: fs, Args: []string{"-fireplace=false"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.HasFireplace || h.Material != "wood" {
+		t.Errorf("house = %+v", *h)
+	}
+	for _, r := range res {
+		if r.Name == "material" && r.Source != FromDefault {
+			t.Errorf("material from %s, want default", r.Source)
+		}
+	}
+}
+
+func TestLoadTwiceWithSameFlags(t *testing.T) {
+	fs := flag.NewFlagSet("house", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	floors := fs.Int("floors", 1, "the program's own floors flag")
+	for _, args := range [][]string{{"-floors", "3"}, {"-floors", "4", "-material", "brick"}} {
+		h, _, err := HouseOptions.Load(Sources{Flags: fs, Args: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Floors != *floors {
+			t.Errorf("Load(%v) floors = %d, want %d", args, h.Floors, *floors)
+		}
+	}
+	if *floors != 4 {
+		t.Errorf("own flag = %d, want 4", *floors)
+	}
+	if f := fs.Lookup("material"); f == nil || f.Value.String() != "brick" {
+		t.Errorf("material flag = %v", f)
+	}
+}
+
+func TestLoadForgetsEarlierFlags(t *testing.T) {
+	fs := flag.NewFlagSet("house", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if _, _, err := HouseOptions.Load(Sources{Flags: fs, Args: []string{"-floors", "3"}}); err != nil {
+		t.Fatal(err)
+	}
+	h, res, err := HouseOptions.Load(Sources{Flags: fs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Floors != 2 {
+		t.Errorf("floors = %d, want the default 2", h.Floors)
+	}
+	for _, r := range res {
+		if r.Source != FromDefault {
+			t.Errorf("%s from %s, want default", r.Name, r.Source)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "house.json")
+	os.WriteFile(file, []byte(`{"floors":"many","colour":"red"}`), 0o644)
+	env := map[string]string{"HOUSE_FLOORS": "0"}
+	_, _, err := HouseOptions.Load(Sources{
+		File:      file,
+		EnvPrefix: "HOUSE_",
+		LookupEnv: func(k string) (string, bool) { v, ok := env[k]; return v, ok },
+	})
+	if err == nil {
+		t.Fatal("Load succeeded")
+	}
+	for _, want := range []string{`invalid int "many"`, `unknown parameter "colour"`, "$HOUSE_FLOORS", "at least 1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("APP_", "max-retries.count"); got != "APP_MAX_RETRIES_COUNT" {
+		t.Errorf("EnvName = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package prototype
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Depth says whether a field is copied or shared by a clone.
+type Depth int
+
+const (
+	// Deep copies the field, recursively for child inodes.
+	Deep Depth = iota
+	// Shallow shares the field with the original.
+	Shallow
+)
+
+// CycleMode says what a clone does when a folder contains itself, directly
+// or through its children.
+type CycleMode int
+
+const (
+	// PreserveCycles gives the copy the same cycle as the original.
+	PreserveCycles CycleMode = iota
+	// RejectCycles fails the clone with a *CycleError.
+	RejectCycles
+)
+
+// ClonePolicy controls how an inode tree is cloned.
+type ClonePolicy struct {
+	// Rename returns the name of a clone. Nil keeps the original name.
+	Rename func(name string) string
+	// Data is the depth of File contents.
+	Data Depth
+	// Children is the depth of Folder children. With Shallow the cloned
+	// folder holds the original children.
+	Children Depth
+	// Cycles says what to do with a folder that contains itself.
+	Cycles CycleMode
+}
+
+// DefaultPolicy is the policy used by Clone: every name gets a "_clone"
+// suffix and the whole tree is copied.
+var DefaultPolicy = ClonePolicy{Rename: Suffix("_clone")}
+
+// Suffix returns a Rename function that appends suffix.
+func Suffix(suffix string) func(string) string {
+	return func(name string) string {
+		return name + suffix
+	}
+}
+
+// CycleError is returned when RejectCycles meets a folder that contains
+// itself. Path lists the folders from the root of the clone to the repeat.
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("prototype: cycle at %s", strings.Join(e.Path, "/"))
+}
+
+// CloneWith clones n following p. An inode reachable through several
+// parents is cloned once and the clones stay shared, just like the
+// originals.
+func CloneWith(n Inode, p ClonePolicy) (Inode, error) {
+	c := &cloner{policy: p, clones: make(map[Inode]Inode), active: make(map[Inode]bool)}
+	return c.clone(n)
+}
+
+type cloner struct {
+	policy ClonePolicy
+	clones map[Inode]Inode
+	active map[Inode]bool
+	path   []string
+}
+
+func (c *cloner) name(name string) string {
+	if c.policy.Rename == nil {
+		return name
+	}
+	return c.policy.Rename(name)
+}
+
+func (c *cloner) clone(n Inode) (Inode, error) {
+	if clone, ok := c.clones[n]; ok {
+		if c.active[n] && c.policy.Cycles == RejectCycles {
+			return nil, &CycleError{Path: append(append([]string(nil), c.path...), n.Name())}
+		}
+		return clone, nil
+	}
+	switch n := n.(type) {
+	case *File:
+		clone := &File{name: c.name(n.name), data: n.data, modTime: n.modTime}
+		if c.policy.Data == Deep {
+			clone.data = append([]byte(nil), n.data...)
+		}
+		c.clones[n] = clone
+		return clone, nil
+	case *Folder:
+		clone := &Folder{name: c.name(n.name), modTime: n.modTime}
+		// Record the clone before visiting the children so that shared
+		// and cyclic references resolve to it.
+		c.clones[n] = clone
+		if c.policy.Children == Shallow {
+			clone.children = append([]Inode(nil), n.children...)
+			return clone, nil
+		}
+		c.active[n] = true
+		c.path = append(c.path, n.name)
+		for _, child := range n.children {
+			cc, err := c.clone(child)
+			if err != nil {
+				return nil, err
+			}
+			clone.children = append(clone.children, cc)
+		}
+		c.path = c.path[:len(c.path)-1]
+		delete(c.active, n)
+		return clone, nil
+	}
+	return nil, fmt.Errorf("prototype: cannot clone %T", n)
+}
@@ -0,0 +1,99 @@
+package prototype
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCloneRenamesAndCopies(t *testing.T) {
+	file := NewFile("File1", []byte("data"))
+	folder := NewFolder("Folder1", file)
+
+	clone := folder.Clone().(*Folder)
+	if clone.Name() != "Folder1_clone" || clone.Children()[0].Name() != "File1_clone" {
+		t.Errorf("clone names = %s, %s", clone.Name(), clone.Children()[0].Name())
+	}
+	cf := clone.Children()[0].(*File)
+	if cf == file {
+		t.Fatal("clone shares the file")
+	}
+	cf.Data()[0] = 'D'
+	if string(file.Data()) != "data" {
+		t.Error("deep clone shares file data")
+	}
+}
+
+func TestCloneShallowPolicy(t *testing.T) {
+	file := NewFile("File1", []byte("data"))
+	folder := NewFolder("Folder1", file)
+
+	c, err := CloneWith(folder, ClonePolicy{Children: Shallow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := c.(*Folder)
+	if clone.Name() != "Folder1" {
+		t.Errorf("nil Rename changed the name to %s", clone.Name())
+	}
+	if clone.Children()[0] != file {
+		t.Error("shallow clone copied its children")
+	}
+	clone.add(NewFile("File2", nil))
+	if len(folder.Children()) != 1 {
+		t.Error("adding to the clone changed the original")
+	}
+
+	c, err = CloneWith(file, ClonePolicy{Data: Shallow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.(*File).Data()[0] = 'D'
+	if string(file.Data()) != "Data" {
+		t.Error("shallow data was copied")
+	}
+}
+
+func TestClonePreservesSharing(t *testing.T) {
+	shared := NewFile("shared", []byte("x"))
+	root := NewFolder("root", NewFolder("a", shared), NewFolder("b", shared))
+
+	clone := root.Clone().(*Folder)
+	a := clone.Children()[0].(*Folder).Children()[0]
+	b := clone.Children()[1].(*Folder).Children()[0]
+	if a != b {
+		t.Error("shared file was cloned twice")
+	}
+	if a == Inode(shared) {
+		t.Error("shared file was not cloned")
+	}
+}
+
+func TestCloneCycles(t *testing.T) {
+	root := NewFolder("root")
+	sub := NewFolder("sub", root)
+	root.add(sub)
+
+	clone := root.Clone().(*Folder)
+	csub := clone.Children()[0].(*Folder)
+	if csub.Children()[0] != Inode(clone) {
+		t.Error("cycle was not reproduced in the clone")
+	}
+
+	_, err := CloneWith(root, ClonePolicy{Cycles: RejectCycles})
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("CloneWith = %v, want *CycleError", err)
+	}
+	if got := strings.Join(cycle.Path, "/"); got != "root/sub/root" {
+		t.Errorf("cycle path = %s, want root/sub/root", got)
+	}
+}
+
+func TestCloneRejectCyclesAllowsSharing(t *testing.T) {
+	shared := NewFolder("shared")
+	root := NewFolder("root", shared, NewFolder("other", shared))
+	if _, err := CloneWith(root, ClonePolicy{Cycles: RejectCycles}); err != nil {
+		t.Errorf("shared folder reported as a cycle: %v", err)
+	}
+}
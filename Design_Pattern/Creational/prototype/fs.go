@@ -0,0 +1,341 @@
+package prototype
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is an in-memory file system over a Folder tree. It implements fs.FS,
+// fs.ReadDirFS, fs.ReadFileFS and fs.StatFS, so it works with fs.WalkDir,
+// template.ParseFS and http.FS, and adds operations to change the tree.
+// Once a tree is wrapped it should only be changed through the FS.
+type FS struct {
+	mu   sync.RWMutex
+	root *Folder
+}
+
+// NewFS returns a file system rooted at root. A nil root starts empty.
+func NewFS(root *Folder) *FS {
+	if root == nil {
+		root = NewFolder(".")
+	}
+	return &FS{root: root}
+}
+
+// Root returns the folder at the root of the file system.
+func (f *FS) Root() *Folder {
+	return f.root
+}
+
+// lookup finds the inode at name. The caller must hold f.mu.
+func (f *FS) lookup(op, name string) (Inode, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	var node Inode = f.root
+	if name == "." {
+		return node, nil
+	}
+	for _, elem := range strings.Split(name, "/") {
+		dir, ok := node.(*Folder)
+		if !ok {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		if node, _ = dir.child(elem); node == nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	return node, nil
+}
+
+// parent finds the folder that holds name, which must not be the root.
+// The caller must hold f.mu.
+func (f *FS) parent(op, name string) (*Folder, string, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	dir, base := path.Split(name)
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		dir = "."
+	}
+	node, err := f.lookup(op, dir)
+	if err != nil {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	folder, ok := node.(*Folder)
+	if !ok {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: errors.New("parent is not a directory")}
+	}
+	return folder, base, nil
+}
+
+// Open opens the named file or directory for reading.
+func (f *FS) Open(name string) (fs.File, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	switch n := node.(type) {
+	case *File:
+		return &openFile{info: n.info(), r: bytes.NewReader(n.data)}, nil
+	case *Folder:
+		return &openDir{info: n.info(), entries: entries(n)}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the entries of the named directory sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := node.(*Folder)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return entries(dir), nil
+}
+
+// Stat returns information about the named file or directory.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return node.info(), nil
+}
+
+// ReadFile returns a copy of the contents of the named file.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node, err := f.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := node.(*File)
+	if !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+	return append([]byte(nil), file.data...), nil
+}
+
+// WriteFile creates the named file, or replaces its contents if it exists.
+// The parent directory must exist.
+func (f *FS) WriteFile(name string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dir, base, err := f.parent("writefile", name)
+	if err != nil {
+		return err
+	}
+	data = append([]byte(nil), data...)
+	switch n, _ := dir.child(base); n := n.(type) {
+	case nil:
+		dir.add(NewFile(base, data))
+	case *File:
+		n.data = data
+		n.modTime = time.Now()
+	default:
+		return &fs.PathError{Op: "writefile", Path: name, Err: errors.New("is a directory")}
+	}
+	return nil
+}
+
+// Mkdir creates the named directory. Its parent must exist.
+func (f *FS) Mkdir(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dir, base, err := f.parent("mkdir", name)
+	if err != nil {
+		return err
+	}
+	if n, _ := dir.child(base); n != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	dir.add(NewFolder(base))
+	return nil
+}
+
+// MkdirAll creates the named directory and any missing parents.
+func (f *FS) MkdirAll(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dir := f.root
+	for _, elem := range strings.Split(name, "/") {
+		switch n, _ := dir.child(elem); n := n.(type) {
+		case nil:
+			next := NewFolder(elem)
+			dir.add(next)
+			dir = next
+		case *Folder:
+			dir = n
+		default:
+			return &fs.PathError{Op: "mkdir", Path: name, Err: errors.New("not a directory")}
+		}
+	}
+	return nil
+}
+
+// Remove deletes the named file or empty directory.
+func (f *FS) Remove(name string) error {
+	return f.remove("remove", name, false)
+}
+
+// RemoveAll deletes the named file or directory and everything in it.
+func (f *FS) RemoveAll(name string) error {
+	return f.remove("removeall", name, true)
+}
+
+func (f *FS) remove(op, name string, all bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dir, base, err := f.parent(op, name)
+	if err != nil {
+		return err
+	}
+	n, i := dir.child(base)
+	if n == nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if folder, ok := n.(*Folder); ok && !all && len(folder.children) > 0 {
+		return &fs.PathError{Op: op, Path: name, Err: errors.New("directory not empty")}
+	}
+	dir.remove(i)
+	return nil
+}
+
+// Rename moves oldname to newname. newname must not exist, and a directory
+// cannot be moved inside itself.
+func (f *FS) Rename(oldname, newname string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	oldDir, oldBase, err := f.parent("rename", oldname)
+	if err != nil {
+		return err
+	}
+	n, i := oldDir.child(oldBase)
+	if n == nil {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	if _, ok := n.(*Folder); ok && strings.HasPrefix(newname+"/", oldname+"/") {
+		return &fs.PathError{Op: "rename", Path: newname, Err: errors.New("cannot move a directory inside itself")}
+	}
+	newDir, newBase, err := f.parent("rename", newname)
+	if err != nil {
+		return err
+	}
+	if existing, _ := newDir.child(newBase); existing != nil {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrExist}
+	}
+	oldDir.remove(i)
+	n.setName(newBase)
+	newDir.add(n)
+	return nil
+}
+
+func entries(dir *Folder) []fs.DirEntry {
+	list := make([]fs.DirEntry, len(dir.children))
+	for i, c := range dir.children {
+		list[i] = fs.FileInfoToDirEntry(c.info())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// fileInfo implements fs.FileInfo for files and folders.
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() any           { return nil }
+
+func (f *File) info() *fileInfo {
+	return &fileInfo{name: f.name, size: int64(len(f.data)), mode: 0o644, modTime: f.modTime}
+}
+
+func (f *Folder) info() *fileInfo {
+	return &fileInfo{name: f.name, mode: fs.ModeDir | 0o755, modTime: f.modTime}
+}
+
+// openFile is a file opened for reading. It reads the contents the file
+// had when it was opened.
+type openFile struct {
+	info *fileInfo
+	r    *bytes.Reader
+}
+
+func (o *openFile) Stat() (fs.FileInfo, error) { return o.info, nil }
+func (o *openFile) Read(p []byte) (int, error) { return o.r.Read(p) }
+func (o *openFile) Close() error               { return nil }
+
+func (o *openFile) Seek(offset int64, whence int) (int64, error) {
+	return o.r.Seek(offset, whence)
+}
+
+func (o *openFile) ReadAt(p []byte, off int64) (int, error) {
+	return o.r.ReadAt(p, off)
+}
+
+// openDir is a directory opened for reading its entries.
+type openDir struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *openDir) Close() error               { return nil }
+
+func (d *openDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *openDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
+
+var (
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+)
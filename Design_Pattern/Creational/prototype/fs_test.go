@@ -0,0 +1,124 @@
+package prototype
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+)
+
+func newTestFS() *FS {
+	return NewFS(NewFolder("Folder1",
+		NewFile("README", []byte("hello")),
+		NewFolder("templates",
+			NewFile("page.tmpl", []byte(`{{define "page"}}<h1>{{.}}</h1>{{end}}`)),
+			NewFolder("partials", NewFile("nav.tmpl", []byte(`{{define "nav"}}nav{{end}}`))),
+		),
+		NewFolder("empty"),
+	))
+}
+
+func TestFSConformance(t *testing.T) {
+	if err := fstest.TestFS(newTestFS(), "README", "templates/page.tmpl", "templates/partials/nav.tmpl", "empty"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSWalkAndParse(t *testing.T) {
+	fsys := newTestFS()
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ". README empty templates templates/page.tmpl templates/partials templates/partials/nav.tmpl"
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("walked %s, want %s", got, want)
+	}
+
+	tmpl, err := template.ParseFS(fsys, "templates/*.tmpl", "templates/partials/*.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := tmpl.ExecuteTemplate(&b, "page", "title"); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "<h1>title</h1>" {
+		t.Errorf("page = %q", b.String())
+	}
+}
+
+func TestFSChanges(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("a/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("a/b/c.txt", []byte("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("a/b/c.txt", []byte("two")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := fsys.ReadFile("a/b/c.txt"); err != nil || string(data) != "two" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if err := fsys.WriteFile("missing/c.txt", nil); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("WriteFile without parent = %v, want ErrNotExist", err)
+	}
+	if err := fsys.Mkdir("a"); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkdir existing = %v, want ErrExist", err)
+	}
+
+	if err := fsys.Rename("a/b", "moved"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("moved/c.txt"); err != nil {
+		t.Errorf("after rename: %v", err)
+	}
+	if _, err := fsys.Stat("a/b"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("old path still exists: %v", err)
+	}
+	if err := fsys.Rename("moved", "moved/inner"); err == nil {
+		t.Error("moved a directory inside itself")
+	}
+	if err := fsys.Rename("README", "empty"); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Rename onto existing = %v, want ErrExist", err)
+	}
+
+	if err := fsys.Remove("moved"); err == nil {
+		t.Error("Remove deleted a non-empty directory")
+	}
+	if err := fsys.RemoveAll("moved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Remove("empty"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Remove("empty"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("second Remove = %v, want ErrNotExist", err)
+	}
+}
+
+func TestFSInvalidPaths(t *testing.T) {
+	fsys := newTestFS()
+	for _, name := range []string{"/README", "../README", "templates/"} {
+		if _, err := fsys.Open(name); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Open(%q) = %v, want ErrInvalid", name, err)
+		}
+	}
+	if err := fsys.Remove("."); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Remove(.) = %v, want ErrInvalid", err)
+	}
+	if _, err := fsys.ReadFile("templates"); err == nil {
+		t.Error("ReadFile read a directory")
+	}
+}
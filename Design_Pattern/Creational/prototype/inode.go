@@ -0,0 +1,111 @@
+// Package prototype grows the Inode, File and Folder prototypes from
+// prototype.md into an in-memory file system.
+package prototype
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Inode is the prototype interface shared by files and folders.
+type Inode interface {
+	Name() string
+	Print(w io.Writer, indentation string)
+	Clone() Inode
+	info() *fileInfo
+	setName(name string)
+}
+
+// File is a concrete prototype holding data.
+type File struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+// NewFile returns a file holding data.
+func NewFile(name string, data []byte) *File {
+	return &File{name: name, data: data, modTime: time.Now()}
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+
+// Data returns the contents of the file.
+func (f *File) Data() []byte {
+	return f.data
+}
+
+func (f *File) Print(w io.Writer, indentation string) {
+	fmt.Fprintln(w, indentation+f.name)
+}
+
+// Clone copies the file with DefaultPolicy.
+func (f *File) Clone() Inode {
+	clone, _ := CloneWith(f, DefaultPolicy)
+	return clone
+}
+
+func (f *File) setName(name string) {
+	f.name = name
+}
+
+// Folder is a concrete prototype holding other inodes.
+type Folder struct {
+	name     string
+	children []Inode
+	modTime  time.Time
+}
+
+// NewFolder returns a folder holding children.
+func NewFolder(name string, children ...Inode) *Folder {
+	return &Folder{name: name, children: children, modTime: time.Now()}
+}
+
+func (f *Folder) Name() string {
+	return f.name
+}
+
+// Children returns the inodes directly inside the folder.
+func (f *Folder) Children() []Inode {
+	return f.children
+}
+
+func (f *Folder) Print(w io.Writer, indentation string) {
+	fmt.Fprintln(w, indentation+f.name)
+	for _, i := range f.children {
+		i.Print(w, indentation+indentation)
+	}
+}
+
+// Clone copies the folder and everything in it with DefaultPolicy. Shared
+// children stay shared and cycles are reproduced, so it always terminates.
+func (f *Folder) Clone() Inode {
+	clone, _ := CloneWith(f, DefaultPolicy)
+	return clone
+}
+
+func (f *Folder) setName(name string) {
+	f.name = name
+}
+
+func (f *Folder) child(name string) (Inode, int) {
+	for i, c := range f.children {
+		if c.Name() == name {
+			return c, i
+		}
+	}
+	return nil, -1
+}
+
+func (f *Folder) add(n Inode) {
+	f.children = append(f.children, n)
+	f.modTime = time.Now()
+}
+
+func (f *Folder) remove(i int) {
+	f.children = append(f.children[:i:i], f.children[i+1:]...)
+	f.modTime = time.Now()
+}
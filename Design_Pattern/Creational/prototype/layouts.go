@@ -0,0 +1,48 @@
+package prototype
+
+import (
+	"errors"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/option"
+)
+
+// Layouts holds standard folder layouts.
+var Layouts = NewInodeRegistry()
+
+func init() {
+	Layouts.MustRegister("go-module", NewFolder("module",
+		NewFile("go.mod", []byte("module example.com/module\n")),
+		NewFile("README.md", nil),
+		NewFolder("cmd"),
+		NewFolder("internal"),
+	))
+	Layouts.MustRegister("website", NewFolder("site",
+		NewFile("index.html", nil),
+		NewFolder("css", NewFile("site.css", nil)),
+		NewFolder("images"),
+	))
+}
+
+// Named renames the root of a cloned layout.
+func Named(name string) option.Option[Inode] {
+	return option.NewE("Named", func(n *Inode) error {
+		if name == "" {
+			return errors.New("name must not be empty")
+		}
+		(*n).setName(name)
+		return nil
+	})
+}
+
+// WithFile adds a file to the root of a cloned layout, replacing a file of
+// the same name.
+func WithFile(name string, data []byte) option.Option[Inode] {
+	return option.NewE("WithFile", func(n *Inode) error {
+		folder, ok := (*n).(*Folder)
+		if !ok {
+			return errors.New("template is not a folder")
+		}
+		fsys := NewFS(folder)
+		return fsys.WriteFile(name, data)
+	})
+}
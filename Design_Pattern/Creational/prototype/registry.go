@@ -0,0 +1,92 @@
+package prototype
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/factory"
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/option"
+)
+
+// Cloner is implemented by prototypes that know how to copy themselves.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Registry is a prototype manager: named template objects are registered
+// once and handed out as clones, with options applied to the clone.
+type Registry[T any] struct {
+	mu     sync.RWMutex
+	clone  func(T) T
+	protos map[string]T
+}
+
+// NewRegistry returns a registry for prototypes that implement Cloner.
+func NewRegistry[T Cloner[T]]() *Registry[T] {
+	return NewRegistryFunc(func(t T) T { return t.Clone() })
+}
+
+// NewRegistryFunc returns a registry that copies prototypes with clone.
+// It suits types that have no Clone method, such as plain structs.
+func NewRegistryFunc[T any](clone func(T) T) *Registry[T] {
+	return &Registry[T]{clone: clone, protos: make(map[string]T)}
+}
+
+// NewInodeRegistry returns a registry of folder and file templates. Clones
+// keep the names of the template.
+func NewInodeRegistry() *Registry[Inode] {
+	return NewRegistryFunc(func(n Inode) Inode {
+		clone, _ := CloneWith(n, ClonePolicy{})
+		return clone
+	})
+}
+
+// Register stores a copy of proto under name, so later changes to proto do
+// not affect the template.
+func (r *Registry[T]) Register(name string, proto T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.protos[name]; ok {
+		return fmt.Errorf("prototype: %q already registered", name)
+	}
+	r.protos[name] = r.clone(proto)
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry[T]) MustRegister(name string, proto T) {
+	if err := r.Register(name, proto); err != nil {
+		panic(err)
+	}
+}
+
+// New returns a clone of the template registered under name with
+// overrides applied to it.
+func (r *Registry[T]) New(name string, overrides ...option.Option[T]) (T, error) {
+	r.mu.RLock()
+	proto, ok := r.protos[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, &factory.UnknownNameError{Name: name, Suggestions: factory.Suggest(name, r.Names())}
+	}
+	clone := r.clone(proto)
+	if _, err := option.Apply(&clone, overrides...); err != nil {
+		var zero T
+		return zero, fmt.Errorf("prototype %q: %w", name, err)
+	}
+	return clone, nil
+}
+
+// Names returns the registered template names in sorted order.
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.protos))
+	for name := range r.protos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,87 @@
+package prototype
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/factory"
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/option"
+)
+
+func TestLayouts(t *testing.T) {
+	n, err := Layouts.New("go-module", Named("service"), WithFile("main.go", []byte("package main\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := NewFS(n.(*Folder))
+	if n.Name() != "service" {
+		t.Errorf("root = %s, want service", n.Name())
+	}
+	for _, name := range []string{"go.mod", "main.go", "cmd"} {
+		if _, err := fsys.Stat(name); err != nil {
+			t.Errorf("clone is missing %s: %v", name, err)
+		}
+	}
+
+	// The template is not changed by the overrides of a clone.
+	again, err := Layouts.New("go-module")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Name() != "module" {
+		t.Errorf("template root renamed to %s", again.Name())
+	}
+	if _, err := NewFS(again.(*Folder)).Stat("main.go"); err == nil {
+		t.Error("file added to a clone appeared in the template")
+	}
+
+	if _, err := Layouts.New("go-module", Named("")); err == nil {
+		t.Error("empty name accepted")
+	}
+}
+
+func TestRegistryUnknownName(t *testing.T) {
+	_, err := Layouts.New("websites")
+	var unknown *factory.UnknownNameError
+	if !errors.As(err, &unknown) || len(unknown.Suggestions) == 0 || unknown.Suggestions[0] != "website" {
+		t.Errorf("New(websites) = %v, want a suggestion of website", err)
+	}
+}
+
+func TestRegistryCopiesOnRegister(t *testing.T) {
+	folder := NewFolder("tmpl")
+	r := NewInodeRegistry()
+	r.MustRegister("tmpl", folder)
+	folder.add(NewFile("late", nil))
+
+	n, err := r.New("tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.(*Folder).Children()) != 0 {
+		t.Error("change to the original reached the template")
+	}
+	if err := r.Register("tmpl", folder); err == nil {
+		t.Error("duplicate Register succeeded")
+	}
+}
+
+func TestRegistryFuncHouses(t *testing.T) {
+	houses := NewRegistryFunc(func(h option.House) option.House { return h })
+	bungalow, err := option.NewHouse(option.Bungalow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	houses.MustRegister("bungalow", *bungalow)
+
+	brick, err := houses.New("bungalow", option.WithMaterial("brick"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brick.Material != "brick" || brick.Floors != 1 {
+		t.Errorf("brick bungalow = %+v", brick)
+	}
+	if _, err := houses.New("bungalow", option.WithFloors(0)); err == nil {
+		t.Error("invalid override accepted")
+	}
+}
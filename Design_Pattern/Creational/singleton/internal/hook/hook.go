@@ -0,0 +1,10 @@
+// Package hook holds the key that unlocks the test hooks of singleton.Lazy.
+// Being internal, it can only be imported from the singleton tree, so
+// singletontest can call the hooks and production code cannot.
+package hook
+
+// Key is passed to Lazy.SwapForTest. Its unexported field keeps other
+// packages from writing a value of a matching type.
+type Key struct {
+	key struct{}
+}
@@ -0,0 +1,75 @@
+// Package singleton provides Lazy, a race-free lazily initialised value
+// for global services that tests can swap out.
+package singleton
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/singleton/internal/hook"
+)
+
+// Lazy holds a value that is created on first use. It is safe for
+// concurrent use: the value is created at most once at a time, and once it
+// has been created every caller sees the same value.
+//
+// Unlike sync.Once, a failed initialisation is not remembered. The next
+// call to Get runs the init function again.
+type Lazy[T any] struct {
+	mu    sync.Mutex
+	init  func() (T, error)
+	value atomic.Pointer[T]
+}
+
+// New returns a Lazy that creates its value with init.
+func New[T any](init func() (T, error)) *Lazy[T] {
+	return &Lazy[T]{init: init}
+}
+
+// Get returns the value, creating it if this is the first successful call.
+func (l *Lazy[T]) Get() (T, error) {
+	if v := l.value.Load(); v != nil {
+		return *v, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v := l.value.Load(); v != nil {
+		return *v, nil
+	}
+	v, err := l.init()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	l.value.Store(&v)
+	return v, nil
+}
+
+// MustGet is like Get but panics if the value cannot be created.
+func (l *Lazy[T]) MustGet() T {
+	v, err := l.Get()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Initialized reports whether the value has been created.
+func (l *Lazy[T]) Initialized() bool {
+	return l.value.Load() != nil
+}
+
+// SwapForTest replaces the value with v, or forgets it if v is nil, and
+// returns a function that restores the previous state. It is the hook
+// behind the singletontest package; other packages cannot call it because
+// they cannot make a hook.Key.
+func (l *Lazy[T]) SwapForTest(_ hook.Key, v *T) (restore func()) {
+	l.mu.Lock()
+	old := l.value.Swap(v)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.value.Store(old)
+	}
+}
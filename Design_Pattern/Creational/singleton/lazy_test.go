@@ -0,0 +1,74 @@
+package singleton
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type service struct {
+	name string
+}
+
+func TestLazyInitialisesOnce(t *testing.T) {
+	var calls atomic.Int32
+	l := New(func() (*service, error) {
+		calls.Add(1)
+		return &service{name: "db"}, nil
+	})
+	if l.Initialized() {
+		t.Fatal("initialised before first Get")
+	}
+
+	var wg sync.WaitGroup
+	got := make([]*service, 50)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = l.MustGet()
+		}(i)
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("init ran %d times, want 1", n)
+	}
+	for _, s := range got {
+		if s != got[0] {
+			t.Fatal("callers saw different instances")
+		}
+	}
+}
+
+func TestLazyRetriesFailedInit(t *testing.T) {
+	errDown := errors.New("down")
+	fail := true
+	l := New(func() (string, error) {
+		if fail {
+			return "", errDown
+		}
+		return "up", nil
+	})
+	if _, err := l.Get(); !errors.Is(err, errDown) {
+		t.Fatalf("Get = %v, want errDown", err)
+	}
+	if l.Initialized() {
+		t.Error("failed init marked the value as created")
+	}
+	fail = false
+	if v, err := l.Get(); err != nil || v != "up" {
+		t.Errorf("Get after recovery = %q, %v", v, err)
+	}
+}
+
+func TestLazyMustGetPanics(t *testing.T) {
+	l := New(func() (int, error) { return 0, errors.New("boom") })
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGet did not panic")
+		}
+	}()
+	l.MustGet()
+}
@@ -0,0 +1,24 @@
+// Package singletontest swaps the value of a singleton.Lazy for the duration
+// of a test.
+package singletontest
+
+import (
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/singleton"
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/singleton/internal/hook"
+)
+
+// Set replaces the value of l with v until the test finishes, then
+// restores the previous state.
+func Set[T any](tb testing.TB, l *singleton.Lazy[T], v T) {
+	tb.Helper()
+	tb.Cleanup(l.SwapForTest(hook.Key{}, &v))
+}
+
+// Reset forgets the value of l so that the next Get runs its init function
+// again. The previous state is restored when the test finishes.
+func Reset[T any](tb testing.TB, l *singleton.Lazy[T]) {
+	tb.Helper()
+	tb.Cleanup(l.SwapForTest(hook.Key{}, nil))
+}
@@ -0,0 +1,37 @@
+package singletontest
+
+import (
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/singleton"
+)
+
+type service struct{ name string }
+
+var global = singleton.New(func() (*service, error) { return &service{name: "real"}, nil })
+
+func TestSetAndReset(t *testing.T) {
+	real := global.MustGet()
+	t.Run("fake", func(t *testing.T) {
+		Set(t, global, &service{name: "fake"})
+		if got := global.MustGet().name; got != "fake" {
+			t.Errorf("got %s, want fake", got)
+		}
+	})
+	if global.MustGet() != real {
+		t.Error("Set did not restore the real service")
+	}
+
+	t.Run("reset", func(t *testing.T) {
+		Reset(t, global)
+		if global.Initialized() {
+			t.Error("Reset left the value set")
+		}
+		if global.MustGet() == real {
+			t.Error("Get after reset returned the old instance")
+		}
+	})
+	if global.MustGet() != real {
+		t.Error("Reset did not restore the real service")
+	}
+}
@@ -0,0 +1,232 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Behavioral/iterator"
+)
+
+// publisher is a callback based subscription API.
+type publisher struct {
+	mu  sync.Mutex
+	cbs map[int]func(int)
+	id  int
+}
+
+func (p *publisher) subscribe(cb func(int)) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cbs == nil {
+		p.cbs = make(map[int]func(int))
+	}
+	p.id++
+	id := p.id
+	p.cbs[id] = cb
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.cbs, id)
+	}
+}
+
+func (p *publisher) publish(v int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, cb := range p.cbs {
+		cb(v)
+	}
+}
+
+func TestCallbackToChan(t *testing.T) {
+	var p publisher
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := CallbackToChan(ctx, 2, p.subscribe)
+	p.publish(1)
+	p.publish(2)
+	p.publish(3) // dropped, the buffer is full
+	if v := <-ch; v != 1 {
+		t.Errorf("first value = %d, want 1", v)
+	}
+	if v := <-ch; v != 2 {
+		t.Errorf("second value = %d, want 2", v)
+	}
+
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after cancel")
+	}
+	p.mu.Lock()
+	n := len(p.cbs)
+	p.mu.Unlock()
+	if n != 0 {
+		t.Error("callback not removed after cancel")
+	}
+	p.publish(4) // must not panic on the closed channel
+}
+
+func TestChanToCallback(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	var got []int
+	if err := ChanToCallback(context.Background(), ch, func(v int) { got = append(got, v) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %v, want [1 2]", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ChanToCallback(ctx, make(chan int), func(int) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ChanToCallback = %v, want context.Canceled", err)
+	}
+}
+
+func TestFuture(t *testing.T) {
+	f := Async(context.Background(), func(context.Context) (int, error) { return 42, nil })
+	if v, err := f.Await(context.Background()); v != 42 || err != nil {
+		t.Errorf("Await = %d, %v", v, err)
+	}
+
+	errLate := errors.New("late")
+	f = FromCallback(func(done func(int, error)) {
+		go func() {
+			done(1, nil)
+			done(2, errLate)
+		}()
+	})
+	<-f.Done()
+	if v, err := f.Await(context.Background()); v != 1 || err != nil {
+		t.Errorf("Await = %d, %v, want the first result", v, err)
+	}
+
+	slow := Async(context.Background(), func(ctx context.Context) (int, error) {
+		time.Sleep(time.Second)
+		return 0, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := slow.Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Await = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestSync(t *testing.T) {
+	double := Sync(func(ctx context.Context, n int) *Future[int] {
+		return Async(ctx, func(context.Context) (int, error) { return 2 * n, nil })
+	})
+	if v, err := double(context.Background(), 21); v != 42 || err != nil {
+		t.Errorf("double(21) = %d, %v", v, err)
+	}
+}
+
+func TestReaderChunksRoundTrip(t *testing.T) {
+	data := strings.Repeat("adapter ", 100)
+	// OneByteReader returns short reads, so chunks depend on the reader.
+	it := ReaderChunks(iotest.OneByteReader(strings.NewReader(data)), 16)
+	chunks, err := iterator.Collect(it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range chunks {
+		if len(c) > 16 {
+			t.Fatalf("chunk of %d bytes, want at most 16", len(c))
+		}
+	}
+
+	got, err := io.ReadAll(IteratorReader(iterator.NewSliceIterator(chunks)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != data {
+		t.Error("round trip changed the data")
+	}
+}
+
+func TestReaderChunksError(t *testing.T) {
+	errDisk := errors.New("disk")
+	it := ReaderChunks(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errDisk)), 8)
+	if !it.Next() || string(it.Value()) != "abc" {
+		t.Fatalf("first chunk = %q", it.Value())
+	}
+	if it.Next() {
+		t.Error("Next succeeded after the error")
+	}
+	if !errors.Is(it.Err(), errDisk) {
+		t.Errorf("Err = %v, want errDisk", it.Err())
+	}
+
+	_, err := io.ReadAll(IteratorReader(ReaderChunks(iotest.ErrReader(errDisk), 8)))
+	if !errors.Is(err, errDisk) {
+		t.Errorf("ReadAll = %v, want errDisk", err)
+	}
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+type reply struct {
+	Text string `json:"text"`
+}
+
+func greet(ctx context.Context, g greeting) (reply, error) {
+	if g.Name == "" {
+		return reply{}, &HTTPError{Status: http.StatusUnprocessableEntity, Message: "name is required"}
+	}
+	if g.Name == "crash" {
+		return reply{}, errors.New("boom")
+	}
+	return reply{Text: "hello " + g.Name}, nil
+}
+
+func TestHandlerAndCall(t *testing.T) {
+	h := Handler(greet)
+	call := Call[greeting, reply](h, "/greet")
+
+	r, err := call(context.Background(), greeting{Name: "gopher"})
+	if err != nil || r.Text != "hello gopher" {
+		t.Errorf("call = %+v, %v", r, err)
+	}
+
+	_, err = call(context.Background(), greeting{})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusUnprocessableEntity || httpErr.Message != "name is required" {
+		t.Errorf("call = %v, want 422 name is required", err)
+	}
+	_, err = call(context.Background(), greeting{Name: "crash"})
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusInternalServerError {
+		t.Errorf("call = %v, want 500", err)
+	}
+}
+
+func TestHandlerBodies(t *testing.T) {
+	h := Handler(greet)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status %d, want 400", w.Code)
+	}
+
+	// An empty chunked body decodes as the zero request.
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(nil))
+	r.ContentLength = -1
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("empty chunked body: status %d, want 422", w.Code)
+	}
+}
@@ -0,0 +1,58 @@
+// Package adapter contains reusable adapters between common Go shapes:
+// callbacks and channels, readers and iterators, synchronous functions and
+// futures, HTTP handlers and plain functions. Generate writes adapter
+// structs like WindowsAdapter in adapter.md.
+package adapter
+
+import (
+	"context"
+	"sync"
+)
+
+// CallbackToChan adapts a callback based subscription to a channel.
+// subscribe registers the callback and returns a function that removes it.
+// The channel is closed, and the callback removed, when ctx is done. Values
+// are dropped while the channel buffer is full and nobody is receiving, so
+// a slow reader never blocks the publisher.
+func CallbackToChan[T any](ctx context.Context, buffer int, subscribe func(cb func(T)) (unsubscribe func())) <-chan T {
+	ch := make(chan T, buffer)
+	var mu sync.Mutex
+	closed := false
+	unsubscribe := subscribe(func(v T) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- v:
+		default:
+		}
+	})
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+		mu.Lock()
+		defer mu.Unlock()
+		closed = true
+		close(ch)
+	}()
+	return ch
+}
+
+// ChanToCallback calls cb with every value received from ch until ch is
+// closed or ctx is done. It blocks, so callers usually run it in a
+// goroutine.
+func ChanToCallback[T any](ctx context.Context, ch <-chan T, cb func(T)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			cb(v)
+		}
+	}
+}
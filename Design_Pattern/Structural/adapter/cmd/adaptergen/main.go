@@ -0,0 +1,57 @@
+// Command adaptergen writes an adapter struct implementing an interface by
+// calling the methods of another type. It is meant for go:generate:
+//
+//	//go:generate adaptergen -target Computer -source Windows -map InsertIntoLightningPort=insertIntoUSBPort -o windows_adapter.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Structural/adapter"
+)
+
+func main() {
+	var cfg adapter.GenerateConfig
+	var mapping, out string
+	flag.StringVar(&cfg.Dir, "dir", ".", "directory of the package declaring the types")
+	flag.StringVar(&cfg.Target, "target", "", "interface the adapter implements")
+	flag.StringVar(&cfg.Source, "source", "", "type being adapted")
+	flag.StringVar(&cfg.Name, "name", "", "name of the adapter (default <source>Adapter)")
+	flag.StringVar(&mapping, "map", "", "comma separated TargetMethod=SourceMethod pairs")
+	flag.StringVar(&out, "o", "", "output file (default stdout)")
+	flag.Parse()
+
+	if cfg.Target == "" || cfg.Source == "" {
+		fmt.Fprintln(os.Stderr, "adaptergen: -target and -source are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	cfg.Methods = make(map[string]string)
+	if mapping != "" {
+		for _, pair := range strings.Split(mapping, ",") {
+			target, source, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				fmt.Fprintf(os.Stderr, "adaptergen: invalid mapping %q, want Target=Source\n", pair)
+				os.Exit(2)
+			}
+			cfg.Methods[target] = source
+		}
+	}
+
+	src, err := adapter.Generate(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "adaptergen:", err)
+		os.Exit(1)
+	}
+	if out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "adaptergen:", err)
+		os.Exit(1)
+	}
+}
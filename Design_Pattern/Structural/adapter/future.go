@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+)
+
+// Future is the result of an asynchronous call that will be available
+// later.
+type Future[T any] struct {
+	done  chan struct{}
+	once  sync.Once
+	value T
+	err   error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+func (f *Future[T]) complete(v T, err error) {
+	f.once.Do(func() {
+		f.value, f.err = v, err
+		close(f.done)
+	})
+}
+
+// Async runs a synchronous function in its own goroutine and returns a
+// future for its result.
+func Async[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	f := newFuture[T]()
+	go func() {
+		f.complete(fn(ctx))
+	}()
+	return f
+}
+
+// FromCallback adapts an asynchronous API that reports its result to a
+// callback. Only the first call to done counts.
+func FromCallback[T any](start func(done func(T, error))) *Future[T] {
+	f := newFuture[T]()
+	start(f.complete)
+	return f
+}
+
+// Done returns a channel that is closed once the result is available.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await blocks until the result is available or ctx is done.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Sync adapts a function that returns a future back to a blocking call.
+func Sync[A, T any](fn func(context.Context, A) *Future[T]) func(context.Context, A) (T, error) {
+	return func(ctx context.Context, a A) (T, error) {
+		return fn(ctx, a).Await(ctx)
+	}
+}
@@ -0,0 +1,356 @@
+package adapter
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerateConfig describes an adapter struct to generate.
+type GenerateConfig struct {
+	// Dir is the directory of the package that declares Target and Source.
+	// The adapter is generated into the same package.
+	Dir string
+	// Target is the name of the interface the adapter implements.
+	Target string
+	// Source is the name of the adapted type.
+	Source string
+	// Name is the name of the adapter struct. It defaults to Source
+	// followed by "Adapter".
+	Name string
+	// Methods maps Target methods to the Source methods that implement
+	// them. Target methods not listed map to the Source method of the same
+	// name.
+	Methods map[string]string
+}
+
+// Generate writes the source of an adapter struct that implements the
+// Target interface by calling the mapped methods of Source, such as
+//
+//	type WindowsAdapter struct {
+//		adaptee *Windows
+//	}
+//
+//	func (a *WindowsAdapter) InsertIntoLightningPort() {
+//		a.adaptee.insertIntoUSBPort()
+//	}
+//
+// The declarations are read from the Go files in cfg.Dir, so unexported
+// methods can be mapped too. Parameter and result types of each pair of
+// methods must match exactly. Packages used in the signatures are imported
+// as the files declaring Target and Source import them.
+func Generate(cfg GenerateConfig) ([]byte, error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Source + "Adapter"
+	}
+	pkg, files, err := parseDir(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	iface, ifaceFile, err := findInterface(files, cfg.Target)
+	if err != nil {
+		return nil, err
+	}
+	methods, srcFiles, ptr := findMethods(files, cfg.Source)
+	if methods == nil {
+		return nil, fmt.Errorf("adapter: type %s has no methods in %s", cfg.Source, cfg.Dir)
+	}
+
+	adaptee := cfg.Source
+	if ptr {
+		adaptee = "*" + adaptee
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %s adapts %s to %s.\ntype %s struct {\n\tadaptee %s\n}\n\n", cfg.Name, adaptee, cfg.Target, cfg.Name, adaptee)
+	fmt.Fprintf(&body, "// New%s returns an adapter for adaptee.\nfunc New%s(adaptee %s) *%s {\n\treturn &%s{adaptee: adaptee}\n}\n\n",
+		cfg.Name, cfg.Name, adaptee, cfg.Name, cfg.Name)
+	fmt.Fprintf(&body, "var _ %s = (*%s)(nil)\n", cfg.Target, cfg.Name)
+
+	var missing []string
+	qualifiers := make(map[string]bool)
+	for _, m := range iface.Methods.List {
+		ft, ok := m.Type.(*ast.FuncType)
+		if !ok || len(m.Names) == 0 {
+			return nil, fmt.Errorf("adapter: %s embeds other interfaces, which is not supported", cfg.Target)
+		}
+		name := m.Names[0].Name
+		srcName := name
+		if mapped, ok := cfg.Methods[name]; ok {
+			srcName = mapped
+		}
+		src, ok := methods[srcName]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%s (as %s.%s)", name, cfg.Source, srcName))
+			continue
+		}
+		if sig(ft) != sig(src) {
+			return nil, fmt.Errorf("adapter: %s.%s%s does not match %s.%s%s",
+				cfg.Target, name, sig(ft), cfg.Source, srcName, sig(src))
+		}
+		addQualifiers(qualifiers, ft)
+		params, args := paramList(ft)
+		fmt.Fprintf(&body, "\nfunc (a *%s) %s(%s) %s {\n\t", cfg.Name, name, params, results(ft.Results))
+		if ft.Results != nil && len(ft.Results.List) > 0 {
+			body.WriteString("return ")
+		}
+		fmt.Fprintf(&body, "a.adaptee.%s(%s)\n}\n", srcName, args)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("adapter: %s has no method for %s", cfg.Source, strings.Join(missing, ", "))
+	}
+	imports, err := resolveImports(qualifiers, append([]*ast.File{ifaceFile}, srcFiles...))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by adapter.Generate. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	if len(imports) > 0 {
+		fmt.Fprintf(&buf, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+	}
+	buf.Write(body.Bytes())
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("adapter: formatting generated code: %w", err)
+	}
+	return out, nil
+}
+
+func parseDir(dir string) (string, []*ast.File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Strings(paths)
+	fset := token.NewFileSet()
+	var pkg string
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return "", nil, fmt.Errorf("adapter: no Go files in %s", dir)
+	}
+	return pkg, files, nil
+}
+
+// findInterface returns the interface called name and the file declaring
+// it.
+func findInterface(files []*ast.File, name string) (*ast.InterfaceType, *ast.File, error) {
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != name {
+					continue
+				}
+				iface, ok := ts.Type.(*ast.InterfaceType)
+				if !ok {
+					return nil, nil, fmt.Errorf("adapter: %s is not an interface", name)
+				}
+				return iface, f, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("adapter: interface %s not found", name)
+}
+
+// findMethods returns the methods declared on typ, the files declaring
+// them and whether any of them has a pointer receiver, in which case the
+// adapter holds a pointer.
+func findMethods(files []*ast.File, typ string) (map[string]*ast.FuncType, []*ast.File, bool) {
+	var methods map[string]*ast.FuncType
+	var declaring []*ast.File
+	ptr := false
+	for _, f := range files {
+		found := false
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 {
+				continue
+			}
+			recv := fd.Recv.List[0].Type
+			isPtr := false
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv, isPtr = star.X, true
+			}
+			if id, ok := recv.(*ast.Ident); !ok || id.Name != typ {
+				continue
+			}
+			if methods == nil {
+				methods = make(map[string]*ast.FuncType)
+			}
+			methods[fd.Name.Name] = fd.Type
+			ptr = ptr || isPtr
+			found = true
+		}
+		if found {
+			declaring = append(declaring, f)
+		}
+	}
+	return methods, declaring, ptr
+}
+
+// addQualifiers adds the package names used in the types of ft, such as
+// context in context.Context, to qualifiers.
+func addQualifiers(qualifiers map[string]bool, ft *ast.FuncType) {
+	ast.Inspect(ft, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				qualifiers[id.Name] = true
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// resolveImports returns the import specs, sorted by path, that bring the
+// qualifiers into scope as the first of files that imports them does. A
+// package imported without a name is assumed to be called after the last
+// element of its path.
+func resolveImports(qualifiers map[string]bool, files []*ast.File) ([]string, error) {
+	var specs []string
+	for q := range qualifiers {
+		spec, ok := findImport(q, files)
+		if !ok {
+			return nil, fmt.Errorf("adapter: no import found for package %s", q)
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return importPath(specs[i]) < importPath(specs[j])
+	})
+	return specs, nil
+}
+
+func findImport(qualifier string, files []*ast.File) (string, bool) {
+	for _, f := range files {
+		for _, imp := range f.Imports {
+			p, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			switch {
+			case imp.Name != nil && imp.Name.Name == qualifier:
+				return qualifier + " " + imp.Path.Value, true
+			case imp.Name == nil && path.Base(p) == qualifier:
+				return imp.Path.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+func importPath(spec string) string {
+	return spec[strings.IndexByte(spec, '"'):]
+}
+
+// sig renders the parameter and result types of ft without names.
+func sig(ft *ast.FuncType) string {
+	return "(" + strings.Join(typeList(ft.Params), ", ") + ") (" + strings.Join(typeList(ft.Results), ", ") + ")"
+}
+
+func typeList(fl *ast.FieldList) []string {
+	if fl == nil {
+		return nil
+	}
+	var list []string
+	for _, f := range fl.List {
+		n := max(len(f.Names), 1)
+		for i := 0; i < n; i++ {
+			list = append(list, exprString(f.Type))
+		}
+	}
+	return list
+}
+
+// paramList returns the parameter list of the generated method, naming
+// unnamed parameters and those that would shadow the receiver, and the
+// arguments passing them on. New names are p followed by the position of
+// the parameter, or a higher number if that is taken by another parameter
+// or an identifier in the signature.
+func paramList(ft *ast.FuncType) (string, string) {
+	rename := func(n string) bool { return n == "" || n == "_" || n == "a" }
+	taken := map[string]bool{"a": true}
+	ast.Inspect(ft, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && !rename(id.Name) {
+			taken[id.Name] = true
+		}
+		return true
+	})
+	var params, args []string
+	i := 0
+	for _, f := range ft.Params.List {
+		typ := exprString(f.Type)
+		names := make([]string, 0, len(f.Names))
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, n := range names {
+			if rename(n) {
+				for k := i; ; k++ {
+					if n = fmt.Sprintf("p%d", k); !taken[n] {
+						break
+					}
+				}
+				taken[n] = true
+			}
+			params = append(params, n+" "+typ)
+			arg := n
+			if _, ok := f.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+			i++
+		}
+	}
+	return strings.Join(params, ", "), strings.Join(args, ", ")
+}
+
+func results(fl *ast.FieldList) string {
+	t := typeList(fl)
+	switch len(t) {
+	case 0:
+		return ""
+	case 1:
+		return t[0]
+	}
+	return "(" + strings.Join(t, ", ") + ")"
+}
+
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
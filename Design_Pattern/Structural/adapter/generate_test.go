@@ -0,0 +1,168 @@
+package adapter
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const windowsSrc = `package demo
+
+type Computer interface {
+	InsertIntoLightningPort()
+	Write(a []byte, _, _ int) (int, error)
+	Name() string
+}
+
+type Windows struct{}
+
+func (w *Windows) insertIntoUSBPort()                   {}
+func (w *Windows) write(p []byte, off, n int) (int, error) { return 0, nil }
+func (w Windows) Name() string                          { return "windows" }
+`
+
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "demo.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	dir := writePackage(t, windowsSrc)
+	out, err := Generate(GenerateConfig{
+		Dir:     dir,
+		Target:  "Computer",
+		Source:  "Windows",
+		Methods: map[string]string{"InsertIntoLightningPort": "insertIntoUSBPort", "Write": "write"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(out)
+	for _, want := range []string{
+		"type WindowsAdapter struct {\n\tadaptee *Windows\n}",
+		"func (a *WindowsAdapter) InsertIntoLightningPort() {\n\ta.adaptee.insertIntoUSBPort()\n}",
+		"func (a *WindowsAdapter) Write(p0 []byte, p1 int, p2 int) (int, error) {\n\treturn a.adaptee.write(p0, p1, p2)\n}",
+		"func (a *WindowsAdapter) Name() string {\n\treturn a.adaptee.Name()\n}",
+		"var _ Computer = (*WindowsAdapter)(nil)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code is missing:\n%s\n\ngot:\n%s", want, src)
+		}
+	}
+
+	// The generated file must type-check alongside the package.
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"demo.go": windowsSrc, "adapter.go": src} {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	if _, err := new(types.Config).Check("demo", fset, files, nil); err != nil {
+		t.Errorf("generated code does not compile: %v", err)
+	}
+}
+
+const doerSrc = `package demo
+
+import (
+	"context"
+	iox "io"
+)
+
+type Doer interface {
+	Do(ctx context.Context, n int) error
+	Copy(p1 iox.Writer, _ int, s string) (int64, error)
+}
+`
+
+const legacySrc = `package demo
+
+import (
+	"context"
+	iox "io"
+)
+
+type legacy struct{}
+
+func (l *legacy) run(ctx context.Context, n int) error                 { return ctx.Err() }
+func (l *legacy) Copy(w iox.Writer, n int, s string) (int64, error) { return 0, nil }
+`
+
+func TestGenerateImports(t *testing.T) {
+	dir := writePackage(t, doerSrc)
+	if err := os.WriteFile(filepath.Join(dir, "legacy.go"), []byte(legacySrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Generate(GenerateConfig{
+		Dir:     dir,
+		Target:  "Doer",
+		Source:  "legacy",
+		Methods: map[string]string{"Do": "run"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"import (\n\t\"context\"\n\tiox \"io\"\n)",
+		"func (a *legacyAdapter) Do(ctx context.Context, n int) error {",
+		"func (a *legacyAdapter) Copy(p1 iox.Writer, p2 int, s string) (int64, error) {",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("generated code is missing:\n%s\n\ngot:\n%s", want, out)
+		}
+	}
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	for name, src := range map[string]string{"adapter.go": string(out), "go.mod": "module demo\n\ngo 1.21\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goTool, "build", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go build: %v\n%s\ngenerated:\n%s", err, msg, out)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	dir := writePackage(t, windowsSrc)
+	tests := []struct {
+		name string
+		cfg  GenerateConfig
+		want string
+	}{
+		{"missing method", GenerateConfig{Target: "Computer", Source: "Windows"}, "has no method for InsertIntoLightningPort"},
+		{"signature mismatch", GenerateConfig{Target: "Computer", Source: "Windows",
+			Methods: map[string]string{"InsertIntoLightningPort": "Name"}}, "does not match"},
+		{"unknown interface", GenerateConfig{Target: "Mac", Source: "Windows"}, "interface Mac not found"},
+		{"not an interface", GenerateConfig{Target: "Windows", Source: "Windows"}, "is not an interface"},
+		{"unknown source", GenerateConfig{Target: "Computer", Source: "Linux"}, "type Linux has no methods"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Dir = dir
+			_, err := Generate(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Generate = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HTTPError is an error with an HTTP status code. Handler sends it to the
+// client with that status, and Call returns it for non-2xx responses.
+type HTTPError struct {
+	Status  int
+	Message string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Handler adapts a plain function to an http.Handler. The request body is
+// decoded from JSON into Req and the result encoded as JSON. Errors become
+// a 500 response, or the status of an *HTTPError.
+func Handler[Req, Resp any](fn func(context.Context, Req) (Resp, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		if r.Body != nil && r.ContentLength != 0 {
+			// A chunked request has an unknown length and may still be empty.
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				writeError(w, &HTTPError{Status: http.StatusBadRequest, Message: err.Error()})
+				return
+			}
+		}
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		httpErr = &HTTPError{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+	http.Error(w, httpErr.Message, httpErr.Status)
+}
+
+// Call adapts an http.Handler to a plain function, calling it in memory
+// with a JSON encoded POST request and decoding the JSON response.
+func Call[Req, Resp any](h http.Handler, path string) func(context.Context, Req) (Resp, error) {
+	return func(ctx context.Context, req Req) (Resp, error) {
+		var resp Resp
+		body, err := json.Marshal(req)
+		if err != nil {
+			return resp, err
+		}
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, path, bytes.NewReader(body))
+		if err != nil {
+			return resp, err
+		}
+		r.Header.Set("Content-Type", "application/json")
+		w := &responseBuffer{header: make(http.Header), status: http.StatusOK}
+		h.ServeHTTP(w, r)
+		if w.status < 200 || w.status > 299 {
+			return resp, &HTTPError{Status: w.status, Message: strings.TrimSpace(w.body.String())}
+		}
+		if w.body.Len() > 0 {
+			if err := json.Unmarshal(w.body.Bytes(), &resp); err != nil {
+				return resp, fmt.Errorf("decoding response: %w", err)
+			}
+		}
+		return resp, nil
+	}
+}
+
+// responseBuffer is an in-memory http.ResponseWriter.
+type responseBuffer struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (b *responseBuffer) Header() http.Header { return b.header }
+
+func (b *responseBuffer) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status, b.wroteHeader = status, true
+	}
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
@@ -0,0 +1,59 @@
+package legacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Computer is the modern interface for plugging devices into a machine.
+type Computer interface {
+	Plug(ctx context.Context, req PlugRequest) (PlugResponse, error)
+}
+
+// PlugRequest asks to plug a device in. Watts is the power the device
+// draws to charge, 0 if it does not charge.
+type PlugRequest struct {
+	Device    string
+	Connector string
+	Watts     int
+}
+
+// PlugResponse tells which port the device was plugged into.
+type PlugResponse struct {
+	Port     string
+	Charging bool
+}
+
+// Errors returned by Computer implementations.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrUnsupported    = errors.New("connector not supported")
+	ErrNoFreePort     = errors.New("no free port")
+)
+
+// Mac implements Computer natively with two Lightning ports.
+type Mac struct {
+	used int
+}
+
+// NewMac returns a Mac with all ports free.
+func NewMac() *Mac {
+	return &Mac{}
+}
+
+func (m *Mac) Plug(ctx context.Context, req PlugRequest) (PlugResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return PlugResponse{}, err
+	}
+	switch {
+	case req.Device == "" || req.Watts < 0:
+		return PlugResponse{}, ErrInvalidRequest
+	case req.Connector != "Lightning":
+		return PlugResponse{}, ErrUnsupported
+	case m.used == 2:
+		return PlugResponse{}, ErrNoFreePort
+	}
+	m.used++
+	return PlugResponse{Port: fmt.Sprintf("port %d", m.used), Charging: req.Watts > 0}, nil
+}
@@ -0,0 +1,18 @@
+package legacy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Structural/adapter/legacy"
+	"github.com/rnsasg/GO_Design/Design_Pattern/Structural/adapter/legacy/legacytest"
+)
+
+type plugFunc = func(context.Context, legacy.PlugRequest) (legacy.PlugResponse, error)
+
+func TestComputers(t *testing.T) {
+	legacytest.Run(t, legacytest.ComputerContract, map[string]func() plugFunc{
+		"mac":     func() plugFunc { return legacy.NewMac().Plug },
+		"windows": func() plugFunc { return legacy.NewWindowsAdapter(legacy.NewWindows()).Plug },
+	})
+}
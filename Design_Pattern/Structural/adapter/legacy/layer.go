@@ -0,0 +1,76 @@
+package legacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownCode is wrapped by the error for a status code that is missing
+// from an ErrorTable.
+var ErrUnknownCode = errors.New("unknown legacy status code")
+
+// ErrorTable translates the status codes of a legacy service to errors.
+type ErrorTable[C comparable] struct {
+	// OK is the code the service returns on success.
+	OK C
+	// Errors maps the other codes to errors, usually sentinel errors of
+	// the modern interface.
+	Errors map[C]error
+}
+
+// Translate returns nil for OK, and otherwise a *CodeError wrapping the
+// error for code, or ErrUnknownCode.
+func (t ErrorTable[C]) Translate(code C) error {
+	if code == t.OK {
+		return nil
+	}
+	err, ok := t.Errors[code]
+	if !ok {
+		err = ErrUnknownCode
+	}
+	return &CodeError{Code: code, Err: err}
+}
+
+// CodeError is an error translated from a legacy status code. It unwraps
+// to the translated error, so callers test it with errors.Is.
+type CodeError struct {
+	Code any
+	Err  error
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("%v (legacy code %v)", e.Err, e.Code)
+}
+
+func (e *CodeError) Unwrap() error { return e.Err }
+
+// Layer wraps a legacy call that takes an LReq and returns an LResp and a
+// status code as a modern call from Req to Resp.
+type Layer[Req, Resp, LReq, LResp any, C comparable] struct {
+	Request  *Mapper[Req, LReq]
+	Response *Mapper[LResp, Resp]
+	Errors   ErrorTable[C]
+	Call     func(LReq) (LResp, C)
+}
+
+// Do maps req, calls the legacy service unless ctx is already done, and
+// maps the response or translates the status code.
+func (l *Layer[Req, Resp, LReq, LResp, C]) Do(ctx context.Context, req Req) (Resp, error) {
+	var resp Resp
+	lreq, err := l.Request.Map(req)
+	if err != nil {
+		return resp, fmt.Errorf("legacy: mapping request: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return resp, err
+	}
+	lresp, code := l.Call(lreq)
+	if err := l.Errors.Translate(code); err != nil {
+		return resp, err
+	}
+	if resp, err = l.Response.Map(lresp); err != nil {
+		return resp, fmt.Errorf("legacy: mapping response: %w", err)
+	}
+	return resp, nil
+}
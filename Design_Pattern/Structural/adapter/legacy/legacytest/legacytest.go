@@ -0,0 +1,138 @@
+// Package legacytest checks implementations of an interface, native or
+// adapted, against the same contract.
+package legacytest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Structural/adapter/legacy"
+)
+
+// Case is one check of a contract. The Setup requests are made first and
+// must succeed, then Req must return Want, or an error matching Err.
+type Case[Req, Resp any] struct {
+	Name  string
+	Setup []Req
+	Req   Req
+	Want  Resp
+	Err   error
+	// Canceled runs Req with a canceled context.
+	Canceled bool
+}
+
+// Run checks every implementation against every case. Each case gets a
+// fresh implementation from its constructor, so cases do not depend on each
+// other. Call it from a test with the native and adapted implementations:
+//
+//	type plugFunc = func(context.Context, legacy.PlugRequest) (legacy.PlugResponse, error)
+//
+//	legacytest.Run(t, legacytest.ComputerContract, map[string]func() plugFunc{
+//		"mac":     func() plugFunc { return legacy.NewMac().Plug },
+//		"windows": func() plugFunc { return legacy.NewWindowsAdapter(legacy.NewWindows()).Plug },
+//	})
+func Run[Req, Resp any](t *testing.T, cases []Case[Req, Resp], impls map[string]func() func(context.Context, Req) (Resp, error)) {
+	t.Helper()
+	names := make([]string, 0, len(impls))
+	for name := range impls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		newImpl := impls[name]
+		t.Run(name, func(t *testing.T) {
+			for _, c := range cases {
+				t.Run(c.Name, func(t *testing.T) {
+					call := newImpl()
+					for i, req := range c.Setup {
+						if _, err := call(context.Background(), req); err != nil {
+							t.Fatalf("setup request %d: %v", i, err)
+						}
+					}
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+					if c.Canceled {
+						cancel()
+					}
+					got, err := call(ctx, c.Req)
+					switch {
+					case c.Err != nil && !errors.Is(err, c.Err):
+						t.Fatalf("got error %v, want %v", err, c.Err)
+					case c.Err == nil && err != nil:
+						t.Fatalf("unexpected error: %v", err)
+					case c.Err == nil && !reflect.DeepEqual(got, c.Want):
+						t.Fatalf("got %+v, want %+v", got, c.Want)
+					}
+				})
+			}
+		})
+	}
+}
+
+// ComputerContract is the behaviour every Computer must have.
+var ComputerContract = []Case[legacy.PlugRequest, legacy.PlugResponse]{
+	{
+		Name: "plug",
+		Req:  legacy.PlugRequest{Device: "keyboard", Connector: "Lightning"},
+		Want: legacy.PlugResponse{Port: "port 1"},
+	},
+	{
+		Name: "charge",
+		Req:  legacy.PlugRequest{Device: "phone", Connector: "Lightning", Watts: 20},
+		Want: legacy.PlugResponse{Port: "port 1", Charging: true},
+	},
+	{
+		Name:  "second port",
+		Setup: []legacy.PlugRequest{{Device: "keyboard", Connector: "Lightning"}},
+		Req:   legacy.PlugRequest{Device: "mouse", Connector: "Lightning"},
+		Want:  legacy.PlugResponse{Port: "port 2"},
+	},
+	{
+		Name: "ports full",
+		Setup: []legacy.PlugRequest{
+			{Device: "keyboard", Connector: "Lightning"},
+			{Device: "mouse", Connector: "Lightning"},
+		},
+		Req: legacy.PlugRequest{Device: "phone", Connector: "Lightning"},
+		Err: legacy.ErrNoFreePort,
+	},
+	{
+		Name: "no device",
+		Req:  legacy.PlugRequest{Connector: "Lightning"},
+		Err:  legacy.ErrInvalidRequest,
+	},
+	{
+		Name: "negative watts",
+		Req:  legacy.PlugRequest{Device: "phone", Connector: "Lightning", Watts: -5},
+		Err:  legacy.ErrInvalidRequest,
+	},
+	{
+		Name: "large negative watts",
+		Req:  legacy.PlugRequest{Device: "phone", Connector: "Lightning", Watts: -100000},
+		Err:  legacy.ErrInvalidRequest,
+	},
+	{
+		Name: "unsupported connector",
+		Req:  legacy.PlugRequest{Device: "monitor", Connector: "HDMI"},
+		Err:  legacy.ErrUnsupported,
+	},
+	{
+		Name: "high power",
+		Req:  legacy.PlugRequest{Device: "laptop", Connector: "Lightning", Watts: 100000},
+		Want: legacy.PlugResponse{Port: "port 1", Charging: true},
+	},
+	{
+		Name: "USB connector",
+		Req:  legacy.PlugRequest{Device: "stick", Connector: "USB"},
+		Err:  legacy.ErrUnsupported,
+	},
+	{
+		Name:     "canceled",
+		Req:      legacy.PlugRequest{Device: "keyboard", Connector: "Lightning"},
+		Err:      context.Canceled,
+		Canceled: true,
+	},
+}
@@ -0,0 +1,156 @@
+// Package legacy wraps legacy services behind modern interfaces. A Layer
+// maps the modern request to the legacy one, translates the legacy status
+// code to an error and maps the legacy response back. Run checks that the
+// native and the adapted implementations pass the same contract.
+package legacy
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Field maps the struct field From of the source to the field To of the
+// destination. Without Convert the value is assigned, or converted between
+// numeric types with an overflow check. Convert receives the source field's
+// value and must return a value assignable to the destination field.
+type Field struct {
+	From, To string
+	Convert  func(any) (any, error)
+}
+
+// Mapper copies fields from Src structs to Dst structs. Both types may be
+// unexported but the mapped fields must be exported. Destination fields
+// without a mapping are left zero.
+type Mapper[Src, Dst any] struct {
+	fields []mappedField
+}
+
+type mappedField struct {
+	Field
+	from, to []int
+	toType   reflect.Type
+}
+
+// NewMapper checks the fields against Src and Dst and returns a mapper.
+func NewMapper[Src, Dst any](fields ...Field) (*Mapper[Src, Dst], error) {
+	src := reflect.TypeOf((*Src)(nil)).Elem()
+	dst := reflect.TypeOf((*Dst)(nil)).Elem()
+	if src.Kind() != reflect.Struct || dst.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("legacy: mapping %s to %s: both must be structs", src, dst)
+	}
+	m := &Mapper[Src, Dst]{}
+	for _, f := range fields {
+		from, ok := src.FieldByName(f.From)
+		if !ok {
+			return nil, fmt.Errorf("legacy: %s has no field %s", src, f.From)
+		}
+		to, ok := dst.FieldByName(f.To)
+		if !ok {
+			return nil, fmt.Errorf("legacy: %s has no field %s", dst, f.To)
+		}
+		if !from.IsExported() || !to.IsExported() {
+			return nil, fmt.Errorf("legacy: mapping %s to %s: fields must be exported", f.From, f.To)
+		}
+		if f.Convert == nil && !assignable(from.Type, to.Type) {
+			return nil, fmt.Errorf("legacy: %s.%s (%s) cannot be mapped to %s.%s (%s) without Convert",
+				src, f.From, from.Type, dst, f.To, to.Type)
+		}
+		m.fields = append(m.fields, mappedField{Field: f, from: from.Index, to: to.Index, toType: to.Type})
+	}
+	return m, nil
+}
+
+// MustMapper is like NewMapper but panics on error. It is meant for
+// package level mappers.
+func MustMapper[Src, Dst any](fields ...Field) *Mapper[Src, Dst] {
+	m, err := NewMapper[Src, Dst](fields...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Map returns a Dst with the mapped fields of s.
+func (m *Mapper[Src, Dst]) Map(s Src) (Dst, error) {
+	var d Dst
+	sv := reflect.ValueOf(&s).Elem()
+	dv := reflect.ValueOf(&d).Elem()
+	for _, f := range m.fields {
+		v := sv.FieldByIndex(f.from)
+		if f.Convert != nil {
+			out, err := f.Convert(v.Interface())
+			if err != nil {
+				return d, fmt.Errorf("field %s: %w", f.From, err)
+			}
+			v = reflect.ValueOf(out)
+			if !v.IsValid() {
+				v = reflect.Zero(f.toType)
+			}
+			if !v.Type().AssignableTo(f.toType) {
+				return d, fmt.Errorf("field %s: Convert returned %s, want %s", f.From, v.Type(), f.toType)
+			}
+		} else if v.Type() != f.toType {
+			var err error
+			if v, err = convert(v, f.toType); err != nil {
+				return d, fmt.Errorf("field %s: %w", f.From, err)
+			}
+		}
+		dv.FieldByIndex(f.to).Set(v)
+	}
+	return d, nil
+}
+
+// assignable reports whether a value of type from can be stored in a field
+// of type to, directly or as a numeric conversion.
+func assignable(from, to reflect.Type) bool {
+	if from.AssignableTo(to) {
+		return true
+	}
+	return numeric(from) && numeric(to) || from.Kind() == reflect.String && to.Kind() == reflect.String
+}
+
+func numeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convert converts v to t, failing if the value does not fit.
+func convert(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	overflow := false
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case v.CanInt():
+			overflow = reflect.Zero(t).OverflowInt(v.Int())
+		case v.CanUint():
+			overflow = v.Uint() > 1<<63-1 || reflect.Zero(t).OverflowInt(int64(v.Uint()))
+		case v.CanFloat():
+			overflow = v.Float() != float64(int64(v.Float())) || reflect.Zero(t).OverflowInt(int64(v.Float()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch {
+		case v.CanInt():
+			overflow = v.Int() < 0 || reflect.Zero(t).OverflowUint(uint64(v.Int()))
+		case v.CanUint():
+			overflow = reflect.Zero(t).OverflowUint(v.Uint())
+		case v.CanFloat():
+			overflow = v.Float() < 0 || v.Float() != float64(uint64(v.Float())) || reflect.Zero(t).OverflowUint(uint64(v.Float()))
+		}
+	case reflect.Float32:
+		if v.CanFloat() {
+			overflow = reflect.Zero(t).OverflowFloat(v.Float())
+		}
+	}
+	if overflow {
+		return v, fmt.Errorf("%v does not fit in %s", v, t)
+	}
+	return v.Convert(t), nil
+}
@@ -0,0 +1,106 @@
+package legacy
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestErrorTable(t *testing.T) {
+	errBusy := errors.New("busy")
+	table := ErrorTable[string]{OK: "OK", Errors: map[string]error{"BUSY": errBusy}}
+	if err := table.Translate("OK"); err != nil {
+		t.Errorf("Translate(OK) = %v", err)
+	}
+
+	err := table.Translate("BUSY")
+	var codeErr *CodeError
+	if !errors.Is(err, errBusy) || !errors.As(err, &codeErr) || codeErr.Code != "BUSY" {
+		t.Errorf("Translate(BUSY) = %v, want a *CodeError wrapping errBusy", err)
+	}
+	if err := table.Translate("???"); !errors.Is(err, ErrUnknownCode) {
+		t.Errorf("Translate(???) = %v, want ErrUnknownCode", err)
+	}
+}
+
+type source struct {
+	Name  string
+	Count int64
+	Ratio float64
+	Big   uint64
+	note  string
+}
+
+type dest struct {
+	Title string
+	N     int8
+	Whole int32
+	Small uint16
+	Label string
+}
+
+func TestMapper(t *testing.T) {
+	m, err := NewMapper[source, dest](
+		Field{From: "Name", To: "Title"},
+		Field{From: "Count", To: "N"},
+		Field{From: "Ratio", To: "Whole"},
+		Field{From: "Big", To: "Small"},
+		Field{From: "Name", To: "Label", Convert: func(v any) (any, error) {
+			return strings.ToUpper(v.(string)), nil
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := m.Map(source{Name: "usb", Count: 7, Ratio: 3, Big: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (dest{Title: "usb", N: 7, Whole: 3, Small: 80, Label: "USB"}); got != want {
+		t.Errorf("Map = %+v, want %+v", got, want)
+	}
+
+	for _, s := range []source{
+		{Count: 200},
+		{Count: -129},
+		{Ratio: 1.5},
+		{Ratio: math.MaxInt64},
+		{Big: 1 << 20},
+	} {
+		if _, err := m.Map(s); err == nil || !strings.Contains(err.Error(), "does not fit") {
+			t.Errorf("Map(%+v) = %v, want an overflow error", s, err)
+		}
+	}
+}
+
+func TestNewMapperErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+	}{
+		{"unknown source field", Field{From: "Missing", To: "Title"}},
+		{"unknown destination field", Field{From: "Name", To: "Missing"}},
+		{"unexported field", Field{From: "note", To: "Label"}},
+		{"incompatible types", Field{From: "Name", To: "N"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewMapper[source, dest](tt.field); err == nil {
+			t.Errorf("%s: NewMapper succeeded", tt.name)
+		}
+	}
+	if _, err := NewMapper[source, int](); err == nil {
+		t.Error("NewMapper accepted a non-struct destination")
+	}
+}
+
+func TestMapperConvertResult(t *testing.T) {
+	m := MustMapper[source, dest](Field{From: "Name", To: "N", Convert: func(any) (any, error) { return "x", nil }})
+	if _, err := m.Map(source{}); err == nil {
+		t.Error("Map accepted a Convert result of the wrong type")
+	}
+	m = MustMapper[source, dest](Field{From: "Name", To: "Title", Convert: func(any) (any, error) { return nil, nil }})
+	if got, err := m.Map(source{Name: "x"}); err != nil || got.Title != "" {
+		t.Errorf("nil Convert result = %+v, %v, want the zero value", got, err)
+	}
+}
@@ -0,0 +1,113 @@
+package legacy
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Windows is a legacy machine with a USB API that reports failures as
+// status codes.
+type Windows struct {
+	used int32
+}
+
+// NewWindows returns a Windows machine with all ports free.
+func NewWindows() *Windows {
+	return &Windows{}
+}
+
+// Status codes of the legacy USB API.
+const (
+	usbOK        = 0
+	usbBadArgs   = 3
+	usbNoPort    = 17
+	usbWrongPlug = 42
+)
+
+const (
+	usbMaxPorts    = 2
+	usbPlugUSBType = "USB"
+)
+
+type usbRequest struct {
+	DevName string
+	Plug    string
+	PowerW  int16
+}
+
+type usbResponse struct {
+	PortNo   int32
+	PowerOut uint8
+}
+
+func (w *Windows) insertIntoUSBPort(req usbRequest) (usbResponse, int) {
+	switch {
+	case req.DevName == "" || req.PowerW < 0:
+		return usbResponse{}, usbBadArgs
+	case req.Plug != usbPlugUSBType:
+		return usbResponse{}, usbWrongPlug
+	case w.used == usbMaxPorts:
+		return usbResponse{}, usbNoPort
+	}
+	w.used++
+	resp := usbResponse{PortNo: w.used}
+	if req.PowerW > 0 {
+		resp.PowerOut = 1
+	}
+	return resp, usbOK
+}
+
+// WindowsAdapter makes Windows a Computer.
+type WindowsAdapter struct {
+	layer *Layer[PlugRequest, PlugResponse, usbRequest, usbResponse, int]
+}
+
+// NewWindowsAdapter returns an adapter for w.
+func NewWindowsAdapter(w *Windows) *WindowsAdapter {
+	return &WindowsAdapter{layer: &Layer[PlugRequest, PlugResponse, usbRequest, usbResponse, int]{
+		Request:  plugToUSB,
+		Response: usbToPlug,
+		Errors:   usbErrors,
+		Call:     w.insertIntoUSBPort,
+	}}
+}
+
+func (a *WindowsAdapter) Plug(ctx context.Context, req PlugRequest) (PlugResponse, error) {
+	return a.layer.Do(ctx, req)
+}
+
+var plugToUSB = MustMapper[PlugRequest, usbRequest](
+	Field{From: "Device", To: "DevName"},
+	Field{From: "Connector", To: "Plug", Convert: func(v any) (any, error) {
+		// The adapter turns a Lightning connector into a USB one. Other
+		// connectors, USB included, do not fit the adapter.
+		if v != "Lightning" {
+			return nil, ErrUnsupported
+		}
+		return usbPlugUSBType, nil
+	}},
+	Field{From: "Watts", To: "PowerW", Convert: func(v any) (any, error) {
+		// The legacy API only checks the sign of the power, so clamp what
+		// does not fit in its int16 rather than let it wrap around.
+		return int16(max(min(v.(int), math.MaxInt16), math.MinInt16)), nil
+	}},
+)
+
+var usbToPlug = MustMapper[usbResponse, PlugResponse](
+	Field{From: "PortNo", To: "Port", Convert: func(v any) (any, error) {
+		return fmt.Sprintf("port %d", v), nil
+	}},
+	Field{From: "PowerOut", To: "Charging", Convert: func(v any) (any, error) {
+		return v.(uint8) != 0, nil
+	}},
+)
+
+var usbErrors = ErrorTable[int]{
+	OK: usbOK,
+	Errors: map[int]error{
+		usbBadArgs:   ErrInvalidRequest,
+		usbNoPort:    ErrNoFreePort,
+		usbWrongPlug: ErrUnsupported,
+	},
+}
@@ -0,0 +1,204 @@
+// Package ports models connectors, machine ports and the adapters between
+// them, and finds the shortest chain of adapters that plugs a connector into
+// a machine.
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Port is a kind of connector or socket, such as "Lightning" or "USB-A".
+type Port string
+
+// Machine is something with ports to plug into.
+type Machine struct {
+	Name  string
+	Ports []Port
+}
+
+func (m Machine) has(p Port) bool {
+	for _, q := range m.Ports {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Adapter takes a From connector and plugs into a To port.
+type Adapter struct {
+	Name     string
+	From, To Port
+}
+
+func (a Adapter) String() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return string(a.From) + "→" + string(a.To)
+}
+
+// ErrDuplicate is returned when an adapter name is registered twice.
+var ErrDuplicate = errors.New("ports: adapter already registered")
+
+// Graph holds the known adapters. Ports are the nodes and adapters the
+// edges between them.
+type Graph struct {
+	adapters []Adapter
+	names    map[string]bool
+	edges    map[Port][]Adapter
+}
+
+// NewGraph returns a graph with the given adapters.
+func NewGraph(adapters ...Adapter) (*Graph, error) {
+	g := &Graph{names: make(map[string]bool), edges: make(map[Port][]Adapter)}
+	for _, a := range adapters {
+		if err := g.Add(a); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Add registers an adapter. When several chains are equally short,
+// adapters added earlier are preferred.
+func (g *Graph) Add(a Adapter) error {
+	if a.From == "" || a.To == "" {
+		return fmt.Errorf("ports: adapter %q needs both ports", a.Name)
+	}
+	if a.From == a.To {
+		return fmt.Errorf("ports: adapter %q connects %s to itself", a.Name, a.From)
+	}
+	key := a.String()
+	if g.names[key] {
+		return fmt.Errorf("%w: %s", ErrDuplicate, key)
+	}
+	g.names[key] = true
+	g.adapters = append(g.adapters, a)
+	g.edges[a.From] = append(g.edges[a.From], a)
+	return nil
+}
+
+// Adapters returns the registered adapters in the order they were added.
+func (g *Graph) Adapters() []Adapter {
+	return append([]Adapter(nil), g.adapters...)
+}
+
+// Chain is a sequence of adapters, each plugged into the next.
+type Chain struct {
+	Connector Port
+	Machine   Machine
+	Adapters  []Adapter
+}
+
+// Port returns the machine port the chain ends in.
+func (c Chain) Port() Port {
+	if len(c.Adapters) == 0 {
+		return c.Connector
+	}
+	return c.Adapters[len(c.Adapters)-1].To
+}
+
+func (c Chain) String() string {
+	var b strings.Builder
+	b.WriteString(string(c.Connector))
+	for _, a := range c.Adapters {
+		fmt.Fprintf(&b, " → [%s] → %s", a, a.To)
+	}
+	fmt.Fprintf(&b, " (%s)", c.Machine.Name)
+	return b.String()
+}
+
+// Connect returns the shortest chain of adapters that plugs connector into
+// one of the machine's ports. A connector that fits directly needs no
+// adapters. If there is no chain, the error is a *NoChainError.
+func (g *Graph) Connect(connector Port, m Machine) (Chain, error) {
+	chain := Chain{Connector: connector, Machine: m}
+	if m.has(connector) {
+		return chain, nil
+	}
+
+	// Breadth first search over ports, remembering the adapter used to
+	// reach each one.
+	via := map[Port]Adapter{}
+	seen := map[Port]bool{connector: true}
+	reached := []Port{connector}
+	for queue := []Port{connector}; len(queue) > 0; queue = queue[1:] {
+		for _, a := range g.edges[queue[0]] {
+			if seen[a.To] {
+				continue
+			}
+			seen[a.To] = true
+			via[a.To] = a
+			if m.has(a.To) {
+				for p := a.To; p != connector; p = via[p].From {
+					chain.Adapters = append(chain.Adapters, via[p])
+				}
+				for i, j := 0, len(chain.Adapters)-1; i < j; i, j = i+1, j-1 {
+					chain.Adapters[i], chain.Adapters[j] = chain.Adapters[j], chain.Adapters[i]
+				}
+				return chain, nil
+			}
+			reached = append(reached, a.To)
+			queue = append(queue, a.To)
+		}
+	}
+	return chain, &NoChainError{Connector: connector, Machine: m, Reachable: reached, Into: g.into(m)}
+}
+
+// into returns the ports that some adapter turns into one of m's ports.
+func (g *Graph) into(m Machine) []Port {
+	seen := map[Port]bool{}
+	var ports []Port
+	for _, a := range g.adapters {
+		if m.has(a.To) && !seen[a.From] {
+			seen[a.From] = true
+			ports = append(ports, a.From)
+		}
+	}
+	return ports
+}
+
+// NoChainError explains why a connector cannot be plugged into a machine.
+type NoChainError struct {
+	Connector Port
+	Machine   Machine
+	// Reachable lists the connector and every port it can be adapted to.
+	Reachable []Port
+	// Into lists the ports that some adapter plugs into the machine.
+	Into []Port
+}
+
+func (e *NoChainError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ports: cannot plug %s into %s (ports %s)", e.Connector, e.Machine.Name, join(e.Machine.Ports))
+	if len(e.Reachable) > 1 {
+		fmt.Fprintf(&b, "; %s can be adapted to %s", e.Connector, join(e.Reachable[1:]))
+	} else {
+		fmt.Fprintf(&b, "; no adapter takes %s", e.Connector)
+	}
+	if len(e.Into) > 0 {
+		fmt.Fprintf(&b, "; adapters into %s take %s", e.Machine.Name, join(e.Into))
+	} else {
+		fmt.Fprintf(&b, "; no adapter plugs into %s", e.Machine.Name)
+	}
+	fmt.Fprintf(&b, "; missing an adapter from any of %s to any of %s", join(e.Reachable), join(e.Machine.Ports, e.Into...))
+	return b.String()
+}
+
+// join lists ports in sorted order, skipping duplicates.
+func join(ports []Port, more ...Port) string {
+	seen := map[Port]bool{}
+	var names []string
+	for _, p := range append(append([]Port(nil), ports...), more...) {
+		if !seen[p] {
+			seen[p] = true
+			names = append(names, string(p))
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,104 @@
+package ports
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestGraph(t *testing.T) *Graph {
+	t.Helper()
+	g, err := NewGraph(
+		Adapter{Name: "Lightning to USB-A", From: "Lightning", To: "USB-A"},
+		Adapter{From: "USB-A", To: "USB-C"},
+		Adapter{From: "USB-C", To: "HDMI"},
+		Adapter{Name: "Lightning to USB-C", From: "Lightning", To: "USB-C"},
+		Adapter{From: "VGA", To: "DVI"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestConnect(t *testing.T) {
+	g := newTestGraph(t)
+	tv := Machine{Name: "TV", Ports: []Port{"HDMI"}}
+
+	c, err := g.Connect("Lightning", tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Lightning → [Lightning to USB-C] → USB-C → [USB-C→HDMI] → HDMI (TV)"; c.String() != want {
+		t.Errorf("chain = %s, want %s", c, want)
+	}
+	if c.Port() != "HDMI" {
+		t.Errorf("Port = %s, want HDMI", c.Port())
+	}
+
+	c, err = g.Connect("HDMI", tv)
+	if err != nil || len(c.Adapters) != 0 || c.Port() != "HDMI" {
+		t.Errorf("direct connection = %v, %v", c, err)
+	}
+}
+
+func TestConnectPrefersEarlierAdapters(t *testing.T) {
+	g, err := NewGraph(
+		Adapter{Name: "first", From: "A", To: "B"},
+		Adapter{Name: "second", From: "A", To: "C"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := g.Connect("A", Machine{Name: "m", Ports: []Port{"C", "B"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Adapters) != 1 || c.Adapters[0].Name != "first" {
+		t.Errorf("chain = %s, want the first adapter", c)
+	}
+}
+
+func TestNoChain(t *testing.T) {
+	g := newTestGraph(t)
+	monitor := Machine{Name: "Monitor", Ports: []Port{"DVI"}}
+
+	_, err := g.Connect("Lightning", monitor)
+	var noChain *NoChainError
+	if !errors.As(err, &noChain) {
+		t.Fatalf("Connect = %v, want *NoChainError", err)
+	}
+	for _, want := range []string{
+		"cannot plug Lightning into Monitor (ports DVI)",
+		"Lightning can be adapted to HDMI, USB-A, USB-C",
+		"adapters into Monitor take VGA",
+		"missing an adapter from any of HDMI, Lightning, USB-A, USB-C to any of DVI, VGA",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	_, err = g.Connect("Thunderbolt", Machine{Name: "Toaster", Ports: []Port{"Mains"}})
+	for _, want := range []string{"no adapter takes Thunderbolt", "no adapter plugs into Toaster"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error %v does not contain %q", err, want)
+		}
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	g := newTestGraph(t)
+	if err := g.Add(Adapter{From: "USB-A", To: "USB-C"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Add duplicate = %v, want ErrDuplicate", err)
+	}
+	if err := g.Add(Adapter{Name: "loop", From: "USB-A", To: "USB-A"}); err == nil {
+		t.Error("Add accepted an adapter to the same port")
+	}
+	if err := g.Add(Adapter{Name: "half", From: "USB-A"}); err == nil {
+		t.Error("Add accepted an adapter without a To port")
+	}
+	if n := len(g.Adapters()); n != 5 {
+		t.Errorf("%d adapters after failed adds, want 5", n)
+	}
+}
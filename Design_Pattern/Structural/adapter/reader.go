@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Behavioral/iterator"
+)
+
+// ReaderChunks adapts an io.Reader to an iterator of chunks of at most size
+// bytes. Each chunk is a fresh slice the caller may keep.
+func ReaderChunks(r io.Reader, size int) iterator.Iterator[[]byte] {
+	if size <= 0 {
+		size = 4096
+	}
+	return &chunkIterator{r: r, size: size}
+}
+
+type chunkIterator struct {
+	r    io.Reader
+	size int
+	cur  []byte
+	err  error
+	eof  bool
+}
+
+func (c *chunkIterator) Next() bool {
+	c.cur = nil
+	for !c.eof && c.err == nil {
+		buf := make([]byte, c.size)
+		n, err := c.r.Read(buf)
+		if errors.Is(err, io.EOF) {
+			c.eof = true
+		} else if err != nil {
+			c.err = err
+		}
+		if n > 0 {
+			c.cur = buf[:n]
+			return true
+		}
+	}
+	return false
+}
+
+func (c *chunkIterator) Value() []byte { return c.cur }
+func (c *chunkIterator) Err() error    { return c.err }
+
+// IteratorReader adapts an iterator of byte slices to an io.Reader. The
+// iterator's error, if any, is returned once its values are exhausted.
+func IteratorReader(it iterator.Iterator[[]byte]) io.Reader {
+	return &iteratorReader{it: it}
+}
+
+type iteratorReader struct {
+	it   iterator.Iterator[[]byte]
+	rest []byte
+	done bool
+}
+
+func (r *iteratorReader) Read(p []byte) (int, error) {
+	for len(r.rest) == 0 {
+		if r.done || !r.it.Next() {
+			r.done = true
+			if err := r.it.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		r.rest = r.it.Value()
+	}
+	n := copy(p, r.rest)
+	r.rest = r.rest[n:]
+	return n, nil
+}
@@ -18,7 +18,7 @@ import "math"
 
 //Overly Complex:
 
-func Factorial(n int) int {
+func FactorialLoop(n int) int {
 	if n < 0 {
 		return -1 // Factorial is not defined for negative numbers
 	}
@@ -29,11 +29,11 @@ func Factorial(n int) int {
 	return result
 }
 
-// use function if available
+// use function if available (Go has no math.Factorial, but n! = Γ(n+1))
 
 func Factorial(n int) int {
 	if n < 0 {
 		return -1 // Factorial is not defined for negative numbers
 	}
-	return math.Factorial(n)
+	return int(math.Round(math.Gamma(float64(n + 1))))
 }
@@ -0,0 +1,152 @@
+package document
+
+import "strings"
+
+// BlockKind is the kind of a Block.
+type BlockKind int
+
+const (
+	Paragraph BlockKind = iota
+	Heading
+	ListItem
+	Code
+)
+
+// Block is a unit of a document that formats lay out.
+type Block struct {
+	Kind BlockKind
+	// Level is the heading level, from 1.
+	Level int
+	Text  string
+	// Inline reports whether Text may contain Markdown inline markup.
+	Inline bool
+}
+
+// parseText splits plain text into paragraphs at blank lines. Line breaks
+// within a paragraph become spaces.
+func parseText(s string) []Block {
+	var blocks []Block
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, Block{Kind: Paragraph, Text: strings.Join(para, " ")})
+			para = nil
+		}
+	}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		para = append(para, line)
+	}
+	flush()
+	return blocks
+}
+
+// parseMarkdown parses the Markdown subset documented on MarkdownDocument.
+func parseMarkdown(s string) []Block {
+	var blocks []Block
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, Block{Kind: Paragraph, Text: strings.Join(para, " "), Inline: true})
+			para = nil
+		}
+	}
+	lines := strings.Split(s, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, strings.TrimRight(lines[i], "\r"))
+			}
+			blocks = append(blocks, Block{Kind: Code, Text: strings.Join(code, "\n")})
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text, ok := strings.CutPrefix(trimmed[level:], " ")
+			if !ok || level > 6 {
+				para = append(para, trimmed)
+				continue
+			}
+			flush()
+			blocks = append(blocks, Block{Kind: Heading, Level: level, Text: strings.TrimSpace(text), Inline: true})
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			blocks = append(blocks, Block{Kind: ListItem, Text: strings.TrimSpace(trimmed[2:]), Inline: true})
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// span is a run of inline text.
+type span struct {
+	text         string
+	code, strong bool
+	em           bool
+}
+
+// spans splits Markdown inline markup into spans. Markers without a
+// closing partner are kept as text.
+func spans(s string) []span {
+	var out []span
+	var cur strings.Builder
+	strong, em := false, false
+	emit := func() {
+		if cur.Len() > 0 {
+			out = append(out, span{text: cur.String(), strong: strong, em: em})
+			cur.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '`':
+			if j := strings.IndexByte(s[i+1:], '`'); j >= 0 {
+				emit()
+				out = append(out, span{text: s[i+1 : i+1+j], code: true})
+				i += j + 2
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**"):
+			if strong || strings.Contains(s[i+2:], "**") {
+				emit()
+				strong = !strong
+				i += 2
+				continue
+			}
+		case s[i] == '*':
+			if em || strings.Contains(s[i+1:], "*") {
+				emit()
+				em = !em
+				i++
+				continue
+			}
+		}
+		cur.WriteByte(s[i])
+		i++
+	}
+	emit()
+	return out
+}
+
+// plainText returns the text of b without inline markup.
+func plainText(b Block) string {
+	if !b.Inline {
+		return b.Text
+	}
+	var sb strings.Builder
+	for _, sp := range spans(b.Text) {
+		sb.WriteString(sp.text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,127 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of an Edit.
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// Edit is one line of a diff.
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+func (e Edit) String() string {
+	return string(e.Op) + " " + e.Line
+}
+
+// Diff returns the line edits that turn a into b, using a longest common
+// subsequence so that unchanged lines are kept as Equal.
+func Diff(a, b string) []Edit {
+	x, y := lines(a), lines(b)
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var edits []Edit
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			edits = append(edits, Edit{Equal, x[i]})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Deletions come first, as in diff and git.
+			edits = append(edits, Edit{Delete, x[i]})
+			i++
+		default:
+			edits = append(edits, Edit{Insert, y[j]})
+			j++
+		}
+	}
+	return edits
+}
+
+// DiffVersions returns the diff between two versions of v.
+func DiffVersions(v Versioned, from, to int) ([]Edit, error) {
+	a, err := v.Version(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := v.Version(to)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(a.Content, b.Content), nil
+}
+
+// Changed reports whether edits contain any insertion or deletion.
+func Changed(edits []Edit) bool {
+	for _, e := range edits {
+		if e.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatDiff returns edits one per line, keeping only context lines of
+// unchanged text around each change. Skipped lines are shown as "...".
+// A negative context is treated as 0.
+func FormatDiff(edits []Edit, context int) string {
+	context = max(context, 0)
+	keep := make([]bool, len(edits))
+	for i, e := range edits {
+		if e.Op == Equal {
+			continue
+		}
+		for k := max(i-context, 0); k <= min(i+context, len(edits)-1); k++ {
+			keep[k] = true
+		}
+	}
+	var b strings.Builder
+	skipped := false
+	for i, e := range edits {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			b.WriteString("...\n")
+			skipped = false
+		}
+		fmt.Fprintln(&b, e)
+	}
+	if skipped {
+		b.WriteString("...\n")
+	}
+	return b.String()
+}
+
+func lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,55 @@
+package document
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	edits := Diff("a\nb\nc\n", "a\nc\nd")
+	want := []Edit{{Equal, "a"}, {Delete, "b"}, {Equal, "c"}, {Insert, "d"}}
+	if len(edits) != len(want) {
+		t.Fatalf("Diff = %v, want %v", edits, want)
+	}
+	for i := range want {
+		if edits[i] != want[i] {
+			t.Errorf("edit %d = %v, want %v", i, edits[i], want[i])
+		}
+	}
+	if !Changed(edits) {
+		t.Error("Changed = false")
+	}
+	if Changed(Diff("a\nb", "a\nb\n")) {
+		t.Error("a trailing newline counts as a change")
+	}
+	if edits := Diff("", "x"); len(edits) != 1 || edits[0] != (Edit{Insert, "x"}) {
+		t.Errorf("Diff from empty = %v", edits)
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	d := NewText("title\nbody")
+	d.Write("title\nnew body")
+	edits, err := DiffVersions(d, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatDiff(edits, 1); got != "  title\n- body\n+ new body\n" {
+		t.Errorf("FormatDiff =\n%s", got)
+	}
+	if _, err := DiffVersions(d, 1, 3); err == nil {
+		t.Error("DiffVersions accepted a missing version")
+	}
+}
+
+func TestFormatDiffContext(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7"
+	b := "1\n2\n3\nfour\n5\n6\n7"
+	want := "...\n  3\n- 4\n+ four\n  5\n...\n"
+	if got := FormatDiff(Diff(a, b), 1); got != want {
+		t.Errorf("FormatDiff =\n%s\nwant\n%s", got, want)
+	}
+	if got := FormatDiff(Diff(a, b), -1); got != "...\n- 4\n+ four\n...\n" {
+		t.Errorf("FormatDiff with negative context =\n%s", got)
+	}
+	if got := FormatDiff(Diff(a, a), 2); got != "...\n" {
+		t.Errorf("FormatDiff without changes = %q", got)
+	}
+}
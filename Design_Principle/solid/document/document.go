@@ -0,0 +1,155 @@
+// Package document is the working version of the Interface Segregation
+// example in solid.md. Each role is its own small interface, and every
+// document implements only the roles it supports: a ReadOnlyDocument is a
+// Reader and nothing else, so code that needs a Writer cannot be handed one.
+package document
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Reader is a document that can be read.
+type Reader interface {
+	Read() string
+}
+
+// Writer is a document that can be written. Every write creates a new
+// version and returns its number.
+type Writer interface {
+	Write(content string) int
+}
+
+// Versioned is a document that keeps its earlier versions.
+type Versioned interface {
+	Versions() []Version
+	Version(n int) (Version, error)
+}
+
+// Printer is a document that can be rendered in a Format.
+type Printer interface {
+	Print(w io.Writer, f Format) error
+}
+
+// Version is the content of a document after one write. Versions are
+// numbered from 1.
+type Version struct {
+	Number  int
+	Content string
+	Written time.Time
+}
+
+// history implements Reader, Writer and Versioned for the documents that
+// embed it.
+type history struct {
+	mu       sync.RWMutex
+	versions []Version
+}
+
+func (h *history) Read() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.versions) == 0 {
+		return ""
+	}
+	return h.versions[len(h.versions)-1].Content
+}
+
+func (h *history) Write(content string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := len(h.versions) + 1
+	h.versions = append(h.versions, Version{Number: n, Content: content, Written: time.Now()})
+	return n
+}
+
+func (h *history) Versions() []Version {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]Version(nil), h.versions...)
+}
+
+func (h *history) Version(n int) (Version, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if n < 1 || n > len(h.versions) {
+		return Version{}, fmt.Errorf("document: no version %d, have 1 to %d", n, len(h.versions))
+	}
+	return h.versions[n-1], nil
+}
+
+// TextDocument is plain text. Paragraphs are separated by blank lines.
+type TextDocument struct {
+	history
+}
+
+// NewText returns a text document whose first version is content.
+func NewText(content string) *TextDocument {
+	d := &TextDocument{}
+	d.Write(content)
+	return d
+}
+
+func (d *TextDocument) Print(w io.Writer, f Format) error {
+	return f.Render(w, parseText(d.Read()))
+}
+
+// MarkdownDocument is Markdown. It understands headings, lists, fenced
+// code blocks, paragraphs and inline **strong**, *emphasis* and `code`.
+type MarkdownDocument struct {
+	history
+}
+
+// NewMarkdown returns a Markdown document whose first version is content.
+func NewMarkdown(content string) *MarkdownDocument {
+	d := &MarkdownDocument{}
+	d.Write(content)
+	return d
+}
+
+func (d *MarkdownDocument) Print(w io.Writer, f Format) error {
+	return f.Render(w, parseMarkdown(d.Read()))
+}
+
+// ReadOnlyDocument can only be read. It has no Write method to leave
+// empty or make panic.
+type ReadOnlyDocument struct {
+	r Reader
+}
+
+// NewReadOnly returns a read-only document with the given content.
+func NewReadOnly(content string) *ReadOnlyDocument {
+	return &ReadOnlyDocument{r: fixed(content)}
+}
+
+// ReadOnly returns a read-only view of r. It reads r's current content, but
+// holders of the view cannot write to r.
+func ReadOnly(r Reader) *ReadOnlyDocument {
+	return &ReadOnlyDocument{r: r}
+}
+
+func (d *ReadOnlyDocument) Read() string {
+	return d.r.Read()
+}
+
+type fixed string
+
+func (f fixed) Read() string { return string(f) }
+
+var (
+	_ interface {
+		Reader
+		Writer
+		Versioned
+		Printer
+	} = (*TextDocument)(nil)
+	_ interface {
+		Reader
+		Writer
+		Versioned
+		Printer
+	} = (*MarkdownDocument)(nil)
+	_ Reader = (*ReadOnlyDocument)(nil)
+)
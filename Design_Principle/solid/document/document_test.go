@@ -0,0 +1,73 @@
+package document
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestVersions(t *testing.T) {
+	d := NewText("one")
+	if n := d.Write("two"); n != 2 {
+		t.Errorf("Write = %d, want 2", n)
+	}
+	if d.Read() != "two" {
+		t.Errorf("Read = %q", d.Read())
+	}
+	v, err := d.Version(1)
+	if err != nil || v.Number != 1 || v.Content != "one" || v.Written.IsZero() {
+		t.Errorf("Version(1) = %+v, %v", v, err)
+	}
+	for _, n := range []int{0, 3} {
+		if _, err := d.Version(n); err == nil || !strings.Contains(err.Error(), "have 1 to 2") {
+			t.Errorf("Version(%d) = %v", n, err)
+		}
+	}
+	vs := d.Versions()
+	vs[0].Content = "changed"
+	if v, _ := d.Version(1); v.Content != "one" {
+		t.Error("changing Versions changed the document")
+	}
+	if len(vs) != 2 {
+		t.Errorf("%d versions, want 2", len(vs))
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	var r Reader = NewReadOnly("fixed")
+	if _, ok := r.(Writer); ok {
+		t.Error("a read-only document is a Writer")
+	}
+	if r.Read() != "fixed" {
+		t.Errorf("Read = %q", r.Read())
+	}
+
+	md := NewMarkdown("# Draft")
+	view := ReadOnly(md)
+	md.Write("# Final")
+	if view.Read() != "# Final" {
+		t.Errorf("view reads %q, want the current content", view.Read())
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	d := NewMarkdown("")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.Write("x")
+			d.Read()
+		}()
+	}
+	wg.Wait()
+	for i, v := range d.Versions() {
+		if v.Number != i+1 {
+			t.Fatalf("version %d is numbered %d", i+1, v.Number)
+		}
+	}
+	if n := len(d.Versions()); n != 21 {
+		t.Errorf("%d versions, want 21", n)
+	}
+}
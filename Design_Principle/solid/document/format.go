@@ -0,0 +1,194 @@
+package document
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Format renders the blocks of a document.
+type Format interface {
+	Render(w io.Writer, blocks []Block) error
+}
+
+// Plain renders plain text. Headings are underlined, list items start with
+// "- " and code is indented. Text is wrapped at Width columns, or not at
+// all if Width is 0.
+type Plain struct {
+	Width int
+}
+
+func (p Plain) Render(w io.Writer, blocks []Block) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range plainLines(blocks, p.Width) {
+		bw.WriteString(line)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func plainLines(blocks []Block, width int) []string {
+	var lines []string
+	for i, b := range blocks {
+		// List items stay together; everything else is separated by a
+		// blank line.
+		if i > 0 && !(b.Kind == ListItem && blocks[i-1].Kind == ListItem) {
+			lines = append(lines, "")
+		}
+		text := plainText(b)
+		switch b.Kind {
+		case Heading:
+			wrapped := wrap(text, width)
+			lines = append(lines, wrapped...)
+			underline := "="
+			if b.Level > 1 {
+				underline = "-"
+			}
+			lines = append(lines, strings.Repeat(underline, longest(wrapped)))
+		case ListItem:
+			for j, l := range wrap(text, width-2) {
+				if j == 0 {
+					lines = append(lines, "- "+l)
+				} else {
+					lines = append(lines, "  "+l)
+				}
+			}
+		case Code:
+			for _, l := range strings.Split(text, "\n") {
+				lines = append(lines, "    "+l)
+			}
+		default:
+			lines = append(lines, wrap(text, width)...)
+		}
+	}
+	return lines
+}
+
+// wrap breaks text into lines of at most width columns at spaces. Words
+// longer than width get a line of their own.
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if width <= 0 {
+		return []string{strings.Join(words, " ")}
+	}
+	var lines []string
+	var cur string
+	for _, word := range words {
+		switch {
+		case cur == "":
+			cur = word
+		case len([]rune(cur))+1+len([]rune(word)) <= width:
+			cur += " " + word
+		default:
+			lines = append(lines, cur)
+			cur = word
+		}
+	}
+	return append(lines, cur)
+}
+
+func longest(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		n = max(n, len([]rune(l)))
+	}
+	return n
+}
+
+// HTML renders an HTML fragment.
+type HTML struct{}
+
+func (HTML) Render(w io.Writer, blocks []Block) error {
+	bw := bufio.NewWriter(w)
+	for i, b := range blocks {
+		switch b.Kind {
+		case Heading:
+			fmt.Fprintf(bw, "<h%d>%s</h%d>\n", b.Level, inlineHTML(b), b.Level)
+		case ListItem:
+			if i == 0 || blocks[i-1].Kind != ListItem {
+				bw.WriteString("<ul>\n")
+			}
+			fmt.Fprintf(bw, "<li>%s</li>\n", inlineHTML(b))
+			if i == len(blocks)-1 || blocks[i+1].Kind != ListItem {
+				bw.WriteString("</ul>\n")
+			}
+		case Code:
+			fmt.Fprintf(bw, "<pre><code>%s</code></pre>\n", html.EscapeString(b.Text))
+		default:
+			fmt.Fprintf(bw, "<p>%s</p>\n", inlineHTML(b))
+		}
+	}
+	return bw.Flush()
+}
+
+func inlineHTML(b Block) string {
+	if !b.Inline {
+		return html.EscapeString(b.Text)
+	}
+	var sb strings.Builder
+	for _, sp := range spans(b.Text) {
+		text := html.EscapeString(sp.text)
+		switch {
+		case sp.code:
+			text = "<code>" + text + "</code>"
+		case sp.strong && sp.em:
+			text = "<strong><em>" + text + "</em></strong>"
+		case sp.strong:
+			text = "<strong>" + text + "</strong>"
+		case sp.em:
+			text = "<em>" + text + "</em>"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// Pages renders plain text wrapped at Width columns and split into pages
+// of Lines lines, each ending with a page number. Pages are separated by a
+// form feed, which printers and pagers treat as a page break.
+type Pages struct {
+	Width int
+	Lines int
+}
+
+func (p Pages) Render(w io.Writer, blocks []Block) error {
+	if p.Lines < 1 {
+		return fmt.Errorf("document: pages of %d lines", p.Lines)
+	}
+	// Split into pages, dropping blank lines at the top of a page.
+	pages := [][]string{nil}
+	for _, l := range plainLines(blocks, p.Width) {
+		cur := pages[len(pages)-1]
+		if len(cur) == p.Lines {
+			cur = nil
+			pages = append(pages, cur)
+		}
+		if len(cur) == 0 && l == "" {
+			continue
+		}
+		pages[len(pages)-1] = append(cur, l)
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, page := range pages {
+		if i > 0 {
+			bw.WriteString("\f")
+		}
+		for _, l := range page {
+			bw.WriteString(l)
+			bw.WriteByte('\n')
+		}
+		// Pad short pages so the footer sits at the bottom.
+		for n := len(page); n < p.Lines; n++ {
+			bw.WriteByte('\n')
+		}
+		footer := fmt.Sprintf("- %d of %d -", i+1, len(pages))
+		if pad := (p.Width - len(footer)) / 2; pad > 0 {
+			footer = strings.Repeat(" ", pad) + footer
+		}
+		bw.WriteString(footer + "\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,110 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+const releaseNotes = "# Release notes\n\nVersion **1.2** makes `Save` faster.\n\n- memory store\n- file store\n- sql store\n"
+
+func render(t *testing.T, p Printer, f Format) string {
+	t.Helper()
+	var b strings.Builder
+	if err := p.Print(&b, f); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// TestSolidExample checks the output shown in solid.md.
+func TestSolidExample(t *testing.T) {
+	notes := NewMarkdown(releaseNotes)
+	html := `<h1>Release notes</h1>
+<p>Version <strong>1.2</strong> makes <code>Save</code> faster.</p>
+<ul>
+<li>memory store</li>
+<li>file store</li>
+<li>sql store</li>
+</ul>
+`
+	if got := render(t, notes, HTML{}); got != html {
+		t.Errorf("HTML =\n%s\nwant\n%s", got, html)
+	}
+	pages := "Release notes\n=============\n\nVersion 1.2 makes Save faster.\n          - 1 of 2 -\n" +
+		"\f- memory store\n- file store\n- sql store\n\n          - 2 of 2 -\n"
+	if got := render(t, notes, Pages{Width: 30, Lines: 4}); got != pages {
+		t.Errorf("Pages =\n%q\nwant\n%q", got, pages)
+	}
+	if n := len(ReadOnly(notes).Read()); n != 95 {
+		t.Errorf("%d bytes, want 95", n)
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	blocks := parseMarkdown("## Two\n#Not a heading\ntext\n\n* item\n```\n  code\n\n```\n####### seven")
+	want := []Block{
+		{Kind: Heading, Level: 2, Text: "Two", Inline: true},
+		{Kind: Paragraph, Text: "#Not a heading text", Inline: true},
+		{Kind: ListItem, Text: "item", Inline: true},
+		{Kind: Code, Text: "  code\n"},
+		{Kind: Paragraph, Text: "####### seven", Inline: true},
+	}
+	if len(blocks) != len(want) {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	for i := range want {
+		if blocks[i] != want[i] {
+			t.Errorf("block %d = %+v, want %+v", i, blocks[i], want[i])
+		}
+	}
+}
+
+func TestInlineHTML(t *testing.T) {
+	tests := map[string]string{
+		"**bold** and *em*":  "<strong>bold</strong> and <em>em</em>",
+		"***both***":         "<strong><em>both</em></strong>",
+		"`a <b> **c**`":      "<code>a &lt;b&gt; **c**</code>",
+		"2 * 3 = 6":          "2 * 3 = 6",
+		"unclosed **bold":    "unclosed **bold",
+		"x < y & \"quoted\"": "x &lt; y &amp; &#34;quoted&#34;",
+	}
+	for in, want := range tests {
+		if got := inlineHTML(Block{Text: in, Inline: true}); got != want {
+			t.Errorf("inlineHTML(%q) = %q, want %q", in, got, want)
+		}
+	}
+	// Plain text is escaped but not parsed.
+	if got := inlineHTML(Block{Text: "**a**"}); got != "**a**" {
+		t.Errorf("plain text = %q", got)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	doc := NewText("The quick brown fox\njumps over the lazy dog.\n\n\nAnother   paragraph.")
+	want := "The quick brown\nfox jumps over\nthe lazy dog.\n\nAnother\nparagraph.\n"
+	if got := render(t, doc, Plain{Width: 15}); got != want {
+		t.Errorf("Plain =\n%s\nwant\n%s", got, want)
+	}
+
+	md := NewMarkdown("## A long heading\n\n- a list item that wraps\n\n```\nx := 1\n```")
+	want = "A long\nheading\n-------\n\n- a list\n  item\n  that\n  wraps\n\n    x := 1\n"
+	if got := render(t, md, Plain{Width: 10}); got != want {
+		t.Errorf("Plain =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if got := wrap("a verylongword b", 5); strings.Join(got, "|") != "a|verylongword|b" {
+		t.Errorf("wrap = %q", got)
+	}
+	if got := wrap("  a  b ", 0); len(got) != 1 || got[0] != "a b" {
+		t.Errorf("wrap without width = %q", got)
+	}
+}
+
+func TestPagesErrors(t *testing.T) {
+	var b strings.Builder
+	if err := NewText("x").Print(&b, Pages{Width: 10}); err == nil {
+		t.Error("Pages without Lines succeeded")
+	}
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"context"
+	"sync"
+)
+
+// Sent is a message recorded by a FakeSender.
+type Sent struct {
+	To      string
+	Message Message
+}
+
+// FakeSender records messages instead of sending them, for tests. Failed
+// sends are not recorded.
+type FakeSender struct {
+	mu   sync.Mutex
+	sent []Sent
+	fail map[string]error
+	err  error
+}
+
+// Fail makes every later Send return err, or succeed again if err is nil.
+func (f *FakeSender) Fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// FailFor makes sends to one address return err.
+func (f *FakeSender) FailFor(to string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail == nil {
+		f.fail = make(map[string]error)
+	}
+	f.fail[to] = err
+}
+
+func (f *FakeSender) Send(ctx context.Context, to string, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.fail[to]; err != nil {
+		return err
+	}
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, Sent{To: to, Message: msg})
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (f *FakeSender) Sent() []Sent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Sent(nil), f.sent...)
+}
@@ -0,0 +1,135 @@
+// Package notification is the working version of the Dependency Inversion
+// example in solid.md. Service depends only on MessageSender; the SMTP, SMS
+// and fake senders are plugged in per channel.
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Message is a rendered notification.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// MessageSender delivers a message to an address on one channel, such as
+// an email address or a phone number.
+type MessageSender interface {
+	Send(ctx context.Context, to string, msg Message) error
+}
+
+// SenderFunc adapts a function to a MessageSender.
+type SenderFunc func(ctx context.Context, to string, msg Message) error
+
+func (f SenderFunc) Send(ctx context.Context, to string, msg Message) error {
+	return f(ctx, to, msg)
+}
+
+// Channel names a way to reach a recipient.
+type Channel string
+
+// Channels with senders in this package.
+const (
+	Email Channel = "email"
+	SMS   Channel = "sms"
+)
+
+// Recipient is someone to notify. Prefer lists the channels to try, in
+// order; if it is empty the service's default order is used. Channels
+// without an address are skipped.
+type Recipient struct {
+	ID        string
+	Addresses map[Channel]string
+	Prefer    []Channel
+}
+
+// ErrNoChannel is returned when a recipient has no address on any channel
+// the service can send to.
+var ErrNoChannel = errors.New("notification: no usable channel")
+
+// Attempt is one try to deliver a notification.
+type Attempt struct {
+	Channel Channel
+	To      string
+	Err     error
+}
+
+// DeliveryError is returned when every channel failed.
+type DeliveryError struct {
+	Recipient string
+	Attempts  []Attempt
+}
+
+func (e *DeliveryError) Error() string {
+	var parts []string
+	for _, a := range e.Attempts {
+		parts = append(parts, fmt.Sprintf("%s: %v", a.Channel, a.Err))
+	}
+	return fmt.Sprintf("notification: delivery to %s failed: %s", e.Recipient, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of all attempts.
+func (e *DeliveryError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, a := range e.Attempts {
+		errs[i] = a.Err
+	}
+	return errs
+}
+
+// Service sends templated notifications over the channels a recipient
+// prefers, falling back to the next channel when one fails.
+type Service struct {
+	templates *Templates
+	senders   map[Channel]MessageSender
+	defaults  []Channel
+}
+
+// NewService returns a service rendering with templates. defaults is the
+// channel order for recipients without preferences.
+func NewService(templates *Templates, defaults ...Channel) *Service {
+	return &Service{templates: templates, senders: make(map[Channel]MessageSender), defaults: defaults}
+}
+
+// Use sets the sender for a channel.
+func (s *Service) Use(ch Channel, sender MessageSender) {
+	s.senders[ch] = sender
+}
+
+// Notify renders the named template with data and sends it to r. It
+// returns the attempts made, the last of which succeeded unless err is
+// not nil. Failed attempts are returned in a *DeliveryError.
+func (s *Service) Notify(ctx context.Context, r Recipient, template string, data any) ([]Attempt, error) {
+	order := r.Prefer
+	if len(order) == 0 {
+		order = s.defaults
+	}
+	var attempts []Attempt
+	for _, ch := range order {
+		to, ok := r.Addresses[ch]
+		sender := s.senders[ch]
+		if !ok || to == "" || sender == nil {
+			continue
+		}
+		msg, err := s.templates.Render(template, ch, data)
+		if err != nil {
+			return attempts, err
+		}
+		err = sender.Send(ctx, to, msg)
+		attempts = append(attempts, Attempt{Channel: ch, To: to, Err: err})
+		if err == nil {
+			return attempts, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(attempts) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNoChannel, r.ID)
+	}
+	return attempts, &DeliveryError{Recipient: r.ID, Attempts: attempts}
+}
@@ -0,0 +1,177 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Principle/solid/notification/smstest"
+	"github.com/rnsasg/GO_Design/Design_Principle/solid/notification/smtptest"
+)
+
+func newTemplates(t *testing.T) *Templates {
+	t.Helper()
+	tpl := NewTemplates()
+	if err := tpl.Add("welcome", "Welcome, {{.Name}}", "Hi {{.Name}},\nthanks for signing up."); err != nil {
+		t.Fatal(err)
+	}
+	if err := tpl.AddFor(SMS, "welcome", "", "Welcome {{.Name}}!"); err != nil {
+		t.Fatal(err)
+	}
+	return tpl
+}
+
+var alice = Recipient{
+	ID:        "alice",
+	Addresses: map[Channel]string{Email: "alice@example.com", SMS: "+15550100"},
+	Prefer:    []Channel{SMS, Email},
+}
+
+func TestTemplates(t *testing.T) {
+	tpl := newTemplates(t)
+	msg, err := tpl.Render("welcome", Email, map[string]string{"Name": "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "Welcome, Alice" || !strings.HasPrefix(msg.Body, "Hi Alice,") {
+		t.Errorf("email = %+v", msg)
+	}
+	msg, err = tpl.Render("welcome", SMS, map[string]string{"Name": "Alice"})
+	if err != nil || msg.Body != "Welcome Alice!" {
+		t.Errorf("sms = %+v, %v", msg, err)
+	}
+
+	if _, err := tpl.Render("welcome", Email, map[string]string{}); err == nil {
+		t.Error("Render accepted data without Name")
+	}
+	if _, err := tpl.Render("goodbye", Email, nil); err == nil {
+		t.Error("Render found a template that was never added")
+	}
+	if err := tpl.Add("broken", "{{", ""); err == nil {
+		t.Error("Add accepted an invalid template")
+	}
+}
+
+func TestServicePreferencesAndFallback(t *testing.T) {
+	email, sms := &FakeSender{}, &FakeSender{}
+	svc := NewService(newTemplates(t), Email, SMS)
+	svc.Use(Email, email)
+	svc.Use(SMS, sms)
+	data := map[string]string{"Name": "Alice"}
+
+	attempts, err := svc.Notify(context.Background(), alice, "welcome", data)
+	if err != nil || len(attempts) != 1 || attempts[0].Channel != SMS {
+		t.Fatalf("Notify = %+v, %v, want one SMS", attempts, err)
+	}
+
+	errDown := errors.New("gateway down")
+	sms.Fail(errDown)
+	attempts, err = svc.Notify(context.Background(), alice, "welcome", data)
+	if err != nil || len(attempts) != 2 || attempts[1].Channel != Email || !errors.Is(attempts[0].Err, errDown) {
+		t.Fatalf("Notify = %+v, %v, want SMS failure then email", attempts, err)
+	}
+	if got := email.Sent(); len(got) != 1 || got[0].Message.Subject != "Welcome, Alice" {
+		t.Errorf("email sent %+v", got)
+	}
+
+	// Without preferences the service order is used.
+	bob := Recipient{ID: "bob", Addresses: map[Channel]string{Email: "bob@example.com", SMS: "+15550101"}}
+	attempts, err = svc.Notify(context.Background(), bob, "welcome", data)
+	if err != nil || attempts[0].Channel != Email {
+		t.Errorf("Notify(bob) = %+v, %v, want email first", attempts, err)
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	email := &FakeSender{}
+	svc := NewService(newTemplates(t), Email, SMS)
+	svc.Use(Email, email)
+	data := map[string]string{"Name": "Alice"}
+
+	smsOnly := Recipient{ID: "carol", Addresses: map[Channel]string{SMS: "+15550102"}}
+	if _, err := svc.Notify(context.Background(), smsOnly, "welcome", data); !errors.Is(err, ErrNoChannel) {
+		t.Errorf("Notify = %v, want ErrNoChannel", err)
+	}
+
+	errBounce := errors.New("bounced")
+	email.FailFor("alice@example.com", errBounce)
+	_, err := svc.Notify(context.Background(), alice, "welcome", data)
+	var delivery *DeliveryError
+	if !errors.As(err, &delivery) || !errors.Is(err, errBounce) || delivery.Recipient != "alice" {
+		t.Errorf("Notify = %v, want a *DeliveryError wrapping errBounce", err)
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	srv, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	sender := &SMTPSender{Addr: srv.Addr, From: "Alerts <alerts@example.com>"}
+
+	msg := Message{Subject: "Grüße", Body: "line one\n.line two"}
+	if err := sender.Send(context.Background(), "Alice <alice@example.com>", msg); err != nil {
+		t.Fatal(err)
+	}
+	mails := srv.Messages()
+	if len(mails) != 1 || mails[0].From != "alerts@example.com" || mails[0].To[0] != "alice@example.com" {
+		t.Fatalf("received %v", mails)
+	}
+	m, err := mails[0].Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject")); subject != "Grüße" {
+		t.Errorf("subject = %q", subject)
+	}
+	body, _ := io.ReadAll(m.Body)
+	// The server reads the data with CRLF turned into LF.
+	if string(body) != "line one\n.line two\n" {
+		t.Errorf("body = %q", body)
+	}
+
+	srv.Reject("bob@example.com")
+	if err := sender.Send(context.Background(), "bob@example.com", msg); err == nil {
+		t.Error("Send to a rejected address succeeded")
+	}
+	if err := sender.Send(context.Background(), "not an address", msg); err == nil {
+		t.Error("Send to an invalid address succeeded")
+	}
+	if err := sender.Send(context.Background(), "alice@example.com", Message{Subject: "a\r\nBcc: x@y"}); err == nil {
+		t.Error("Send accepted a subject with a line break")
+	}
+}
+
+func TestSMSSender(t *testing.T) {
+	gw := &smstest.Gateway{APIKey: "secret"}
+	srv := httptest.NewServer(gw)
+	defer srv.Close()
+	sender := &SMSSender{URL: srv.URL, From: "ACME", APIKey: "secret"}
+
+	if err := sender.Send(context.Background(), "+15550100", Message{Subject: "ignored", Body: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	got := gw.Messages()
+	if len(got) != 1 || got[0].To != "+15550100" || got[0].Text != "hello" || got[0].From != "ACME" {
+		t.Errorf("gateway got %+v", got)
+	}
+
+	gw.FailNext(1, http.StatusServiceUnavailable)
+	err := sender.Send(context.Background(), "+15550100", Message{Body: "again"})
+	var gwErr *GatewayError
+	if !errors.As(err, &gwErr) || !gwErr.Temporary() {
+		t.Errorf("Send = %v, want a temporary *GatewayError", err)
+	}
+
+	sender.APIKey = "wrong"
+	err = sender.Send(context.Background(), "+15550100", Message{Body: "hello"})
+	if !errors.As(err, &gwErr) || gwErr.Status != http.StatusUnauthorized || gwErr.Temporary() {
+		t.Errorf("Send = %v, want a permanent 401", err)
+	}
+}
@@ -0,0 +1,333 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQueueClosed is returned by Queue.Enqueue after Close.
+var ErrQueueClosed = errors.New("notification: queue is closed")
+
+// Envelope is a message to deliver on one channel.
+type Envelope struct {
+	// ID identifies the message. Enqueue generates one if it is empty.
+	ID      string
+	Channel Channel
+	To      string
+	Message Message
+	// DedupKey identifies duplicates. It defaults to the channel, address,
+	// subject and body.
+	DedupKey string
+}
+
+// QueueOption configures a Queue.
+type QueueOption func(*Queue)
+
+// WithRateLimit allows at most perSecond sends per second on ch, with
+// bursts of up to burst messages. A perSecond of zero or less removes the
+// limit.
+func WithRateLimit(ch Channel, perSecond float64, burst int) QueueOption {
+	return func(q *Queue) {
+		if perSecond <= 0 {
+			delete(q.limits, ch)
+			return
+		}
+		q.limits[ch] = newLimiter(perSecond, burst)
+	}
+}
+
+// WithRetry makes a message be tried up to attempts times. The wait before
+// a retry starts at backoff and doubles each time, up to maxBackoff. Errors
+// with a Temporary method returning false are not retried.
+func WithRetry(attempts int, backoff, maxBackoff time.Duration) QueueOption {
+	return func(q *Queue) {
+		q.attempts, q.backoff, q.maxBackoff = max(attempts, 1), backoff, maxBackoff
+	}
+}
+
+// WithDedupWindow drops messages enqueued within d of an identical one.
+func WithDedupWindow(d time.Duration) QueueOption {
+	return func(q *Queue) {
+		q.window = d
+	}
+}
+
+// WithStatusStore sets where delivery statuses are kept. The default is a
+// MemoryStatusStore.
+func WithStatusStore(s StatusStore) QueueOption {
+	return func(q *Queue) {
+		q.store = s
+	}
+}
+
+// WithWorkers sets how many messages each channel sends concurrently. The
+// default is 1.
+func WithWorkers(n int) QueueOption {
+	return func(q *Queue) {
+		q.workers = max(n, 1)
+	}
+}
+
+// Queue delivers messages asynchronously through a MessageSender per
+// channel, with rate limits, retries and deduplication.
+type Queue struct {
+	mu       sync.Mutex
+	idle     *sync.Cond
+	lanes    map[Channel]*lane
+	limits   map[Channel]*limiter
+	store    StatusStore
+	seen     map[string]seen
+	seenKeys []string // dedup keys in the order they were seen
+	pending  int
+	closed   bool
+	stopping bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	window     time.Duration
+	workers    int
+}
+
+type lane struct {
+	sender  MessageSender
+	limiter *limiter
+	items   []*item
+	cond    *sync.Cond
+}
+
+type item struct {
+	env    Envelope
+	status Status
+}
+
+type seen struct {
+	id string
+	at time.Time
+}
+
+// NewQueue starts a queue sending through the given senders.
+func NewQueue(senders map[Channel]MessageSender, opts ...QueueOption) *Queue {
+	q := &Queue{
+		lanes:      make(map[Channel]*lane),
+		limits:     make(map[Channel]*limiter),
+		store:      &MemoryStatusStore{},
+		seen:       make(map[string]seen),
+		attempts:   5,
+		backoff:    time.Second,
+		maxBackoff: time.Minute,
+		workers:    1,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	q.idle = sync.NewCond(&q.mu)
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	for ch, sender := range senders {
+		l := &lane{sender: sender, limiter: q.limits[ch], cond: sync.NewCond(&q.mu)}
+		q.lanes[ch] = l
+		q.wg.Add(q.workers)
+		for i := 0; i < q.workers; i++ {
+			go q.work(l)
+		}
+	}
+	return q
+}
+
+// Enqueue queues e for delivery and returns its ID. A duplicate is not
+// sent; its status has state Duplicate and names the original.
+func (q *Queue) Enqueue(e Envelope) (string, error) {
+	if e.ID == "" {
+		e.ID = newID()
+	}
+	if e.DedupKey == "" {
+		e.DedupKey = string(e.Channel) + "\x00" + e.To + "\x00" + e.Message.Subject + "\x00" + e.Message.Body
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	st := Status{ID: e.ID, Channel: e.Channel, To: e.To, State: Queued, Enqueued: now, Updated: now}
+	if q.closed {
+		return "", ErrQueueClosed
+	}
+	l, ok := q.lanes[e.Channel]
+	if !ok {
+		return "", fmt.Errorf("notification: no sender for channel %q", e.Channel)
+	}
+	if q.window > 0 {
+		q.expireSeen(now)
+		if s, ok := q.seen[e.DedupKey]; ok {
+			st.State, st.DuplicateOf = Duplicate, s.id
+			q.store.Put(st)
+			return e.ID, nil
+		}
+		q.seen[e.DedupKey] = seen{id: e.ID, at: now}
+		q.seenKeys = append(q.seenKeys, e.DedupKey)
+	}
+	q.store.Put(st)
+	q.pending++
+	l.items = append(l.items, &item{env: e, status: st})
+	l.cond.Signal()
+	return e.ID, nil
+}
+
+// expireSeen forgets the dedup keys seen a window or more before now. Keys
+// are seen in time order, so only the oldest ones need to be looked at.
+func (q *Queue) expireSeen(now time.Time) {
+	n := 0
+	for ; n < len(q.seenKeys); n++ {
+		key := q.seenKeys[n]
+		if now.Sub(q.seen[key].at) < q.window {
+			break
+		}
+		delete(q.seen, key)
+	}
+	q.seenKeys = q.seenKeys[n:]
+}
+
+// Sender returns a MessageSender that enqueues messages for ch, so a
+// Service can send through the queue. Its errors only report whether the
+// message was queued.
+func (q *Queue) Sender(ch Channel) MessageSender {
+	return SenderFunc(func(ctx context.Context, to string, msg Message) error {
+		_, err := q.Enqueue(Envelope{Channel: ch, To: to, Message: msg})
+		return err
+	})
+}
+
+// Status returns the delivery status of the message with the given ID.
+func (q *Queue) Status(id string) (Status, bool) {
+	return q.store.Get(id)
+}
+
+// Close stops accepting messages and waits until every queued message is
+// delivered or has failed, or until ctx is done. Messages still pending
+// then are abandoned in their current state and ctx's error is returned.
+func (q *Queue) Close(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		q.mu.Lock()
+		q.idle.Broadcast()
+		q.mu.Unlock()
+	})
+	defer stop()
+
+	q.mu.Lock()
+	q.closed = true
+	for q.pending > 0 && ctx.Err() == nil {
+		q.idle.Wait()
+	}
+	q.stopping = true
+	for _, l := range q.lanes {
+		l.cond.Broadcast()
+	}
+	abandoned := q.pending > 0
+	q.mu.Unlock()
+
+	q.cancel()
+	q.wg.Wait()
+	if abandoned {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (q *Queue) work(l *lane) {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		for len(l.items) == 0 && !q.stopping {
+			l.cond.Wait()
+		}
+		if q.stopping {
+			q.mu.Unlock()
+			return
+		}
+		it := l.items[0]
+		l.items = l.items[1:]
+		q.mu.Unlock()
+
+		if l.limiter != nil {
+			if err := l.limiter.wait(q.ctx); err != nil {
+				return
+			}
+		}
+		it.status.State = Sending
+		it.status.Attempts++
+		q.update(it)
+
+		err := l.sender.Send(q.ctx, it.env.To, it.env.Message)
+		if err == nil {
+			it.status.State, it.status.LastError = Delivered, ""
+			q.update(it)
+			q.finish()
+			continue
+		}
+		it.status.LastError = err.Error()
+		if it.status.Attempts >= q.attempts || !retryable(err) || q.ctx.Err() != nil {
+			it.status.State = Failed
+			q.update(it)
+			q.finish()
+			continue
+		}
+		it.status.State = Retrying
+		q.update(it)
+		time.AfterFunc(q.delay(it.status.Attempts), func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			if !q.stopping {
+				l.items = append(l.items, it)
+				l.cond.Signal()
+			}
+		})
+	}
+}
+
+func (q *Queue) update(it *item) {
+	it.status.Updated = time.Now()
+	q.store.Put(it.status)
+}
+
+func (q *Queue) finish() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending--
+	if q.pending == 0 {
+		q.idle.Broadcast()
+	}
+}
+
+// delay returns the backoff before retry number n.
+func (q *Queue) delay(n int) time.Duration {
+	d := q.backoff
+	for i := 1; i < n && d < q.maxBackoff; i++ {
+		d *= 2
+	}
+	if q.maxBackoff > 0 && d > q.maxBackoff {
+		d = q.maxBackoff
+	}
+	return d
+}
+
+// retryable reports whether err may go away on retry. Errors that say they
+// are not temporary, like a rejected API key, are not retried.
+func retryable(err error) bool {
+	var t interface{ Temporary() bool }
+	if errors.As(err, &t) {
+		return t.Temporary()
+	}
+	return true
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,231 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakySender fails the first n sends with err.
+type flakySender struct {
+	mu    sync.Mutex
+	n     int
+	err   error
+	calls int
+	times []time.Time
+}
+
+func (f *flakySender) Send(ctx context.Context, to string, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.times = append(f.times, time.Now())
+	if f.calls <= f.n {
+		return f.err
+	}
+	return nil
+}
+
+func closeQueue(t *testing.T, q *Queue) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueueDelivers(t *testing.T) {
+	email := &FakeSender{}
+	q := NewQueue(map[Channel]MessageSender{Email: email}, WithWorkers(3))
+	var ids []string
+	for _, to := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		id, err := q.Enqueue(Envelope{Channel: Email, To: to, Message: Message{Body: "hi"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	closeQueue(t, q)
+
+	if n := len(email.Sent()); n != 3 {
+		t.Errorf("sent %d messages, want 3", n)
+	}
+	for _, id := range ids {
+		if st, ok := q.Status(id); !ok || st.State != Delivered || st.Attempts != 1 {
+			t.Errorf("status %s = %+v", id, st)
+		}
+	}
+	if _, err := q.Enqueue(Envelope{Channel: Email, To: "a@example.com"}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue after Close = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestQueueUnknownChannel(t *testing.T) {
+	q := NewQueue(map[Channel]MessageSender{Email: &FakeSender{}})
+	defer closeQueue(t, q)
+	if _, err := q.Enqueue(Envelope{Channel: SMS, To: "+15550100"}); err == nil {
+		t.Error("Enqueue accepted a channel without a sender")
+	}
+}
+
+func TestQueueRetries(t *testing.T) {
+	flaky := &flakySender{n: 2, err: errors.New("timeout")}
+	q := NewQueue(map[Channel]MessageSender{Email: flaky}, WithRetry(5, 10*time.Millisecond, 15*time.Millisecond))
+	id, _ := q.Enqueue(Envelope{Channel: Email, To: "a@example.com"})
+	closeQueue(t, q)
+
+	st, _ := q.Status(id)
+	if st.State != Delivered || st.Attempts != 3 || st.LastError != "" {
+		t.Errorf("status = %+v, want delivered on the third attempt", st)
+	}
+	if gap := flaky.times[1].Sub(flaky.times[0]); gap < 10*time.Millisecond {
+		t.Errorf("first retry after %v, want at least the backoff", gap)
+	}
+}
+
+func TestQueueGivesUp(t *testing.T) {
+	flaky := &flakySender{n: 10, err: errors.New("timeout")}
+	permanent := &flakySender{n: 10, err: &GatewayError{Status: http.StatusUnauthorized}}
+	q := NewQueue(map[Channel]MessageSender{Email: flaky, SMS: permanent},
+		WithRetry(3, time.Millisecond, time.Millisecond))
+	emailID, _ := q.Enqueue(Envelope{Channel: Email, To: "a@example.com"})
+	smsID, _ := q.Enqueue(Envelope{Channel: SMS, To: "+15550100"})
+	closeQueue(t, q)
+
+	if st, _ := q.Status(emailID); st.State != Failed || st.Attempts != 3 || st.LastError != "timeout" {
+		t.Errorf("email status = %+v, want failed after 3 attempts", st)
+	}
+	if st, _ := q.Status(smsID); st.State != Failed || st.Attempts != 1 {
+		t.Errorf("sms status = %+v, want failed without retries", st)
+	}
+}
+
+func TestQueueDedup(t *testing.T) {
+	email := &FakeSender{}
+	q := NewQueue(map[Channel]MessageSender{Email: email}, WithDedupWindow(time.Hour))
+	alert := Envelope{Channel: Email, To: "ops@example.com", Message: Message{Subject: "disk full"}}
+	first, _ := q.Enqueue(alert)
+	second, _ := q.Enqueue(alert)
+	other, _ := q.Enqueue(Envelope{Channel: Email, To: "ops@example.com", Message: Message{Subject: "cpu hot"}})
+	keyed, _ := q.Enqueue(Envelope{Channel: Email, To: "dev@example.com", DedupKey: "disk", Message: Message{Subject: "a"}})
+	again, _ := q.Enqueue(Envelope{Channel: Email, To: "dev@example.com", DedupKey: "disk", Message: Message{Subject: "b"}})
+	closeQueue(t, q)
+
+	if n := len(email.Sent()); n != 3 {
+		t.Errorf("sent %d messages, want 3", n)
+	}
+	for id, want := range map[string]string{second: first, again: keyed} {
+		if st, _ := q.Status(id); st.State != Duplicate || st.DuplicateOf != want {
+			t.Errorf("status = %+v, want a duplicate of %s", st, want)
+		}
+	}
+	if st, _ := q.Status(other); st.State != Delivered {
+		t.Errorf("different message status = %+v", st)
+	}
+}
+
+func TestQueueDedupWindowExpires(t *testing.T) {
+	email := &FakeSender{}
+	q := NewQueue(map[Channel]MessageSender{Email: email}, WithDedupWindow(20*time.Millisecond))
+	alert := Envelope{Channel: Email, To: "ops@example.com", Message: Message{Subject: "disk full"}}
+	q.Enqueue(alert)
+	q.Enqueue(Envelope{Channel: Email, To: "ops@example.com", Message: Message{Subject: "cpu hot"}})
+	time.Sleep(30 * time.Millisecond)
+	again, _ := q.Enqueue(alert)
+	closeQueue(t, q)
+
+	if st, _ := q.Status(again); st.State != Delivered {
+		t.Errorf("status after the window = %+v, want delivered", st)
+	}
+	if len(q.seen) != 1 || len(q.seenKeys) != 1 {
+		t.Errorf("%d keys and %d in order after expiry, want 1", len(q.seen), len(q.seenKeys))
+	}
+}
+
+func TestQueueRateLimit(t *testing.T) {
+	sender := &flakySender{}
+	q := NewQueue(map[Channel]MessageSender{Email: sender}, WithRateLimit(Email, 50, 2))
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		q.Enqueue(Envelope{Channel: Email, To: "a@example.com", Message: Message{Body: string(rune('a' + i))}})
+	}
+	closeQueue(t, q)
+
+	// Two sends use the burst, the other three wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("5 sends at 50/s with a burst of 2 took %v", elapsed)
+	}
+	if sender.calls != 5 {
+		t.Errorf("sent %d, want 5", sender.calls)
+	}
+}
+
+func TestQueueCloseTimeout(t *testing.T) {
+	block := SenderFunc(func(ctx context.Context, to string, msg Message) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q := NewQueue(map[Channel]MessageSender{Email: block})
+	q.Enqueue(Envelope{Channel: Email, To: "a@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestQueueSender(t *testing.T) {
+	email := &FakeSender{}
+	q := NewQueue(map[Channel]MessageSender{Email: email})
+	svc := NewService(newTemplates(t), Email)
+	svc.Use(Email, q.Sender(Email))
+	if _, err := svc.Notify(context.Background(), alice, "welcome", map[string]string{"Name": "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	closeQueue(t, q)
+	if got := email.Sent(); len(got) != 1 || got[0].To != "alice@example.com" {
+		t.Errorf("sent %+v", got)
+	}
+}
+
+func TestMemoryStatusStoreTTL(t *testing.T) {
+	s := &MemoryStatusStore{TTL: time.Minute}
+	now := time.Now()
+	s.Put(Status{ID: "old", State: Delivered, Updated: now.Add(-2 * time.Minute)})
+	s.Put(Status{ID: "stuck", State: Retrying, Updated: now.Add(-2 * time.Minute)})
+	s.Put(Status{ID: "new", State: Queued, Updated: now})
+	if _, ok := s.Get("old"); ok {
+		t.Error("finished status outlived its TTL")
+	}
+	if _, ok := s.Get("stuck"); !ok {
+		t.Error("unfinished status was dropped")
+	}
+
+	// A status put again after finishing lives for TTL from then.
+	s.Put(Status{ID: "again", State: Failed, Updated: now.Add(-3 * time.Minute)})
+	s.Put(Status{ID: "again", State: Delivered, Updated: now.Add(-30 * time.Second)})
+	s.Put(Status{ID: "new", State: Delivered, Updated: now})
+	if _, ok := s.Get("again"); !ok {
+		t.Error("status put again was dropped with its earlier version")
+	}
+	s.Put(Status{ID: "later", State: Delivered, Updated: now.Add(2 * time.Minute)})
+	if _, ok := s.Get("again"); ok {
+		t.Error("status put again outlived its TTL")
+	}
+	if len(s.finished) != 1 {
+		t.Errorf("%d finished statuses tracked, want only the latest", len(s.finished))
+	}
+}
+
+func TestDelay(t *testing.T) {
+	q := &Queue{backoff: time.Second, maxBackoff: 5 * time.Second}
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := q.delay(n); got != want {
+			t.Errorf("delay(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
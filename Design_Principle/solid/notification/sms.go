@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SMSSender sends text messages through an HTTP gateway. It posts
+//
+//	{"from": "...", "to": "...", "text": "..."}
+//
+// as JSON to URL. The subject is not sent, only the body.
+type SMSSender struct {
+	URL    string
+	From   string
+	APIKey string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// GatewayError is returned when the gateway answers with a non-2xx status.
+type GatewayError struct {
+	Status int
+	Body   string
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("sms gateway returned %d %s: %s", e.Status, http.StatusText(e.Status), e.Body)
+}
+
+// Temporary reports whether the request may succeed if retried.
+func (e *GatewayError) Temporary() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+func (s *SMSSender) Send(ctx context.Context, to string, msg Message) error {
+	payload, err := json.Marshal(struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Text string `json:"text"`
+	}{s.From, to, msg.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &GatewayError{Status: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
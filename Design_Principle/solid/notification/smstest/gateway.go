@@ -0,0 +1,84 @@
+// Package smstest provides a stand-in for an SMS gateway's HTTP API that
+// records the messages it accepts.
+package smstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// SMS is a message accepted by the gateway.
+type SMS struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// Gateway is an http.Handler accepting
+//
+//	POST {"from": "...", "to": "...", "text": "..."}
+//
+// and answering 202 with {"id": "..."}. Serve it with httptest.NewServer.
+type Gateway struct {
+	// APIKey, if set, must be sent as a bearer token.
+	APIKey string
+
+	mu       sync.Mutex
+	messages []SMS
+	failures []int
+}
+
+// FailNext makes the next n requests fail with status.
+func (g *Gateway) FailNext(n, status int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := 0; i < n; i++ {
+		g.failures = append(g.failures, status)
+	}
+}
+
+// Messages returns the messages accepted so far.
+func (g *Gateway) Messages() []SMS {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]SMS(nil), g.messages...)
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if g.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+g.APIKey {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+		return
+	}
+	var sms SMS
+	if err := json.NewDecoder(r.Body).Decode(&sms); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sms.To == "" || sms.Text == "" {
+		http.Error(w, "to and text are required", http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	if len(g.failures) > 0 {
+		status := g.failures[0]
+		g.failures = g.failures[1:]
+		g.mu.Unlock()
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	sms.ID = fmt.Sprintf("sms-%d", len(g.messages)+1)
+	g.messages = append(g.messages, sms)
+	g.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"id": sms.ID})
+}
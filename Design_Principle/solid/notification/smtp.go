@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends email through an SMTP server.
+type SMTPSender struct {
+	// Addr is the host:port of the server.
+	Addr string
+	// From is the sender address, with or without a display name.
+	From string
+	// Auth, if set, is used when the server supports AUTH.
+	Auth smtp.Auth
+	// TLSConfig, if set, is used for STARTTLS when the server offers it.
+	TLSConfig *tls.Config
+	// LocalName is sent with EHLO. It defaults to "localhost".
+	LocalName string
+}
+
+func (s *SMTPSender) Send(ctx context.Context, to string, msg Message) (err error) {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("smtp: sender %q: %w", s.From, err)
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("smtp: recipient %q: %w", to, err)
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return errors.New("smtp: subject contains a line break")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	// Unblock the conversation when ctx is done.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer func() {
+		stop()
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
+	host, _, _ := net.SplitHostPort(s.Addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer c.Close()
+
+	name := s.LocalName
+	if name == "" {
+		name = "localhost"
+	}
+	if err := c.Hello(name); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok && s.TLSConfig != nil {
+		if err := c.StartTLS(s.TLSConfig); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	if s.Auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err := c.Auth(s.Auth); err != nil {
+				return fmt.Errorf("smtp: %w", err)
+			}
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := c.Rcpt(rcpt.Address); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if _, err := w.Write(format(from, rcpt, msg)); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return c.Quit()
+}
+
+// format returns the message with its headers and CRLF line endings.
+func format(from, to *mail.Address, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	if !strings.HasSuffix(body, "\n") {
+		b.WriteString("\r\n")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,183 @@
+// Package smtptest provides a local SMTP server that records the mail it
+// receives, for testing senders without a real mail provider.
+package smtptest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Mail is a message received by the server.
+type Mail struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Message parses the received data.
+func (m Mail) Message() (*mail.Message, error) {
+	return mail.ReadMessage(bytes.NewReader(m.Data))
+}
+
+func (m Mail) String() string {
+	return fmt.Sprintf("from %s to %s, %d bytes", m.From, strings.Join(m.To, ", "), len(m.Data))
+}
+
+// Server is an SMTP server on a local port. It supports EHLO, HELO, MAIL,
+// RCPT, DATA, RSET, NOOP and QUIT, without TLS or authentication.
+type Server struct {
+	// Addr is the host:port the server listens on.
+	Addr string
+
+	ln     net.Listener
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	mails  []Mail
+	reject map[string]bool
+	conns  map[net.Conn]bool
+}
+
+// NewServer starts a server on a free port of 127.0.0.1.
+func NewServer() (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{Addr: ln.Addr().String(), ln: ln, reject: make(map[string]bool), conns: make(map[net.Conn]bool)}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Reject makes the server refuse mail to the given addresses.
+func (s *Server) Reject(addrs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range addrs {
+		s.reject[strings.ToLower(a)] = true
+	}
+}
+
+// Messages returns the mail received so far.
+func (s *Server) Messages() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+// Close stops the server and closes open connections.
+func (s *Server) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...any) bool {
+		return tp.PrintfLine(format, args...) == nil
+	}
+	if !reply("220 smtptest ready") {
+		return
+	}
+	var cur Mail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply("250-smtptest\r\n250 8BITMIME")
+		case "HELO", "NOOP":
+			ok = reply("250 OK")
+		case "MAIL":
+			cur = Mail{From: address(arg, "FROM:")}
+			ok = reply("250 OK")
+		case "RCPT":
+			to := address(arg, "TO:")
+			s.mu.Lock()
+			rejected := s.reject[strings.ToLower(to)]
+			s.mu.Unlock()
+			if rejected {
+				ok = reply("550 mailbox unavailable: %s", to)
+				break
+			}
+			cur.To = append(cur.To, to)
+			ok = reply("250 OK")
+		case "DATA":
+			if len(cur.To) == 0 {
+				ok = reply("503 need RCPT first")
+				break
+			}
+			if !reply("354 end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			cur.Data = data
+			s.mu.Lock()
+			s.mails = append(s.mails, cur)
+			s.mu.Unlock()
+			cur = Mail{}
+			ok = reply("250 OK: queued")
+		case "RSET":
+			cur = Mail{}
+			ok = reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			ok = reply("502 %s not implemented", verb)
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// address extracts the address from "FROM:<a@b>" style arguments.
+func address(arg, prefix string) string {
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+	arg, _, _ = strings.Cut(strings.TrimSpace(arg), " ")
+	return strings.Trim(arg, "<>")
+}
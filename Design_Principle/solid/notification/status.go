@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+// State is where a queued message is in its delivery.
+type State string
+
+const (
+	Queued    State = "queued"
+	Sending   State = "sending"
+	Retrying  State = "retrying"
+	Delivered State = "delivered"
+	Failed    State = "failed"
+	// Duplicate means the message was dropped because an identical one
+	// was enqueued within the dedup window. DuplicateOf names it.
+	Duplicate State = "duplicate"
+)
+
+// Done reports whether s is final.
+func (s State) Done() bool {
+	return s == Delivered || s == Failed || s == Duplicate
+}
+
+// Status is the delivery status of one message.
+type Status struct {
+	ID          string
+	Channel     Channel
+	To          string
+	State       State
+	Attempts    int
+	LastError   string
+	DuplicateOf string
+	Enqueued    time.Time
+	Updated     time.Time
+}
+
+// StatusStore keeps delivery statuses by message ID.
+type StatusStore interface {
+	Put(s Status)
+	Get(id string) (Status, bool)
+}
+
+// MemoryStatusStore is a StatusStore in memory. It keeps finished statuses
+// for TTL, or forever if TTL is zero.
+type MemoryStatusStore struct {
+	TTL time.Duration
+
+	mu       sync.Mutex
+	statuses map[string]Status
+	finished []Status // finished statuses in the order they were put
+}
+
+func (m *MemoryStatusStore) Put(s Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.statuses == nil {
+		m.statuses = make(map[string]Status)
+	}
+	m.statuses[s.ID] = s
+	if m.TTL <= 0 {
+		return
+	}
+	if s.State.Done() {
+		m.finished = append(m.finished, s)
+	}
+	// Statuses finish roughly in time order, so expired ones are found at
+	// the front. One that was put again since is left alone.
+	n := 0
+	for ; n < len(m.finished); n++ {
+		old := m.finished[n]
+		if s.Updated.Sub(old.Updated) <= m.TTL {
+			break
+		}
+		if cur, ok := m.statuses[old.ID]; ok && cur.Updated.Equal(old.Updated) && cur.State.Done() {
+			delete(m.statuses, old.ID)
+		}
+	}
+	m.finished = m.finished[n:]
+}
+
+func (m *MemoryStatusStore) Get(id string) (Status, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.statuses[id]
+	return s, ok
+}
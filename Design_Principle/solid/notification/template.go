@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Templates holds named message templates in text/template syntax. A
+// template can have a variant for a channel, for example a shorter SMS.
+type Templates struct {
+	mu    sync.RWMutex
+	named map[string]messageTemplate
+}
+
+type messageTemplate struct {
+	subject, body *template.Template
+}
+
+// NewTemplates returns an empty template set.
+func NewTemplates() *Templates {
+	return &Templates{named: make(map[string]messageTemplate)}
+}
+
+// Add parses the subject and body of the template name.
+func (t *Templates) Add(name, subject, body string) error {
+	return t.add(name, name, subject, body)
+}
+
+// AddFor parses the variant of the template name used on ch.
+func (t *Templates) AddFor(ch Channel, name, subject, body string) error {
+	return t.add(name, variant(name, ch), subject, body)
+}
+
+func (t *Templates) add(name, key, subject, body string) error {
+	s, err := template.New(name + " subject").Option("missingkey=error").Parse(subject)
+	if err != nil {
+		return fmt.Errorf("notification: template %s: %w", name, err)
+	}
+	b, err := template.New(name + " body").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return fmt.Errorf("notification: template %s: %w", name, err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.named[key] = messageTemplate{subject: s, body: b}
+	return nil
+}
+
+// Render executes the template name with data, using the variant for ch
+// if there is one.
+func (t *Templates) Render(name string, ch Channel, data any) (Message, error) {
+	t.mu.RLock()
+	tpl, ok := t.named[variant(name, ch)]
+	if !ok {
+		tpl, ok = t.named[name]
+	}
+	t.mu.RUnlock()
+	if !ok {
+		return Message{}, fmt.Errorf("notification: no template %q", name)
+	}
+	var subject, body strings.Builder
+	if err := tpl.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("notification: %w", err)
+	}
+	if err := tpl.body.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("notification: %w", err)
+	}
+	return Message{Subject: subject.String(), Body: body.String()}, nil
+}
+
+func variant(name string, ch Channel) string {
+	return name + "@" + string(ch)
+}
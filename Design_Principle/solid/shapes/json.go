@@ -0,0 +1,91 @@
+package shapes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// List is a list of shapes that encodes to JSON as an array of objects,
+// each with a "type" field naming its kind in the Default registry:
+//
+//	[{"type":"circle","center":{"x":0,"y":0},"radius":1}]
+type List []Shape
+
+func (l List) MarshalJSON() ([]byte, error) {
+	return Default.Marshal(l)
+}
+
+func (l *List) UnmarshalJSON(data []byte) error {
+	shapes, err := Default.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	*l = shapes
+	return nil
+}
+
+// Marshal encodes shapes like List, using the kinds of r.
+func (r *Registry) Marshal(shapes []Shape) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, s := range shapes {
+		kind, err := r.Kind(s)
+		if err != nil {
+			return nil, fmt.Errorf("shapes: shape %d: %T is not registered", i, s)
+		}
+		fields, err := json.Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("shapes: shape %d (%s): %w", i, kind, err)
+		}
+		if len(fields) < 2 || fields[0] != '{' {
+			return nil, fmt.Errorf("shapes: shape %d (%s): %T does not encode to a JSON object", i, kind, s)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		typ, _ := json.Marshal(kind)
+		fmt.Fprintf(&buf, `{"type":%s`, typ)
+		if len(fields) > 2 {
+			buf.WriteByte(',')
+		}
+		buf.Write(fields[1:])
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes shapes encoded by Marshal and validates every shape
+// that implements Validator.
+func (r *Registry) Unmarshal(data []byte) ([]Shape, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, fmt.Errorf("shapes: %w", err)
+	}
+	shapes := make([]Shape, 0, len(raws))
+	for i, raw := range raws {
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return nil, fmt.Errorf("shapes: shape %d: %w", i, err)
+		}
+		if head.Type == "" {
+			return nil, fmt.Errorf("shapes: shape %d: missing type", i)
+		}
+		s, err := r.New(head.Type)
+		if err != nil {
+			return nil, fmt.Errorf("shapes: shape %d: %w", i, err)
+		}
+		if err := json.Unmarshal(raw, s); err != nil {
+			return nil, fmt.Errorf("shapes: shape %d (%s): %w", i, head.Type, err)
+		}
+		if v, ok := s.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, fmt.Errorf("shapes: shape %d (%s): %w", i, head.Type, err)
+			}
+		}
+		shapes = append(shapes, s)
+	}
+	return shapes, nil
+}
@@ -0,0 +1,104 @@
+package shapes
+
+import (
+	"errors"
+	"math"
+)
+
+// Polygon is a simple polygon through Points, closed from the last point
+// back to the first.
+type Polygon struct {
+	Points []Point `json:"points"`
+}
+
+func (p *Polygon) Area() float64 {
+	// Shoelace formula.
+	sum := 0.0
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return math.Abs(sum) / 2
+}
+
+func (p *Polygon) Perimeter() float64 {
+	sum := 0.0
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		sum += math.Hypot(b.X-a.X, b.Y-a.Y)
+	}
+	return sum
+}
+
+func (p *Polygon) Bounds() Box {
+	if len(p.Points) == 0 {
+		return Box{}
+	}
+	b := Box{Min: p.Points[0], Max: p.Points[0]}
+	for _, q := range p.Points[1:] {
+		b = b.Union(Box{Min: q, Max: q})
+	}
+	return b
+}
+
+func (p *Polygon) Contains(q Point) bool {
+	// Count the edges a ray from q to the right crosses; an odd count
+	// means q is inside.
+	inside := false
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		if onSegment(q, a, b) {
+			return true
+		}
+		if (a.Y > q.Y) != (b.Y > q.Y) && q.X < a.X+(q.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (p *Polygon) Validate() error {
+	if len(p.Points) < 3 {
+		return errors.New("polygon needs at least 3 points")
+	}
+	if p.Area() == 0 {
+		return errors.New("polygon has no area")
+	}
+	return nil
+}
+
+// onSegment reports whether q lies on the segment from a to b.
+func onSegment(q, a, b Point) bool {
+	const eps = 1e-9
+	cross := (b.X-a.X)*(q.Y-a.Y) - (b.Y-a.Y)*(q.X-a.X)
+	if math.Abs(cross) > eps {
+		return false
+	}
+	return Box{
+		Min: Point{math.Min(a.X, b.X), math.Min(a.Y, b.Y)},
+		Max: Point{math.Max(a.X, b.X), math.Max(a.Y, b.Y)},
+	}.Contains(q)
+}
+
+// Triangle is the polygon through A, B and C.
+type Triangle struct {
+	A Point `json:"a"`
+	B Point `json:"b"`
+	C Point `json:"c"`
+}
+
+func (t *Triangle) polygon() *Polygon {
+	return &Polygon{Points: []Point{t.A, t.B, t.C}}
+}
+
+func (t *Triangle) Area() float64         { return t.polygon().Area() }
+func (t *Triangle) Perimeter() float64    { return t.polygon().Perimeter() }
+func (t *Triangle) Bounds() Box           { return t.polygon().Bounds() }
+func (t *Triangle) Contains(p Point) bool { return t.polygon().Contains(p) }
+
+func (t *Triangle) Validate() error {
+	if t.Area() == 0 {
+		return errors.New("triangle points are collinear")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package shapes
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/factory"
+)
+
+// ErrDuplicate is returned when a kind or a shape type is registered twice.
+var ErrDuplicate = errors.New("shapes: already registered")
+
+// Registry maps the kind names used in JSON to shape types. It is safe for
+// concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	ctors map[string]func() Shape
+	kinds map[reflect.Type]string
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{ctors: make(map[string]func() Shape), kinds: make(map[reflect.Type]string)}
+}
+
+// Default holds the shapes of this package. Register adds to it.
+var Default = NewRegistry()
+
+func init() {
+	Default.MustRegister("rectangle", func() Shape { return &Rectangle{} })
+	Default.MustRegister("circle", func() Shape { return &Circle{} })
+	Default.MustRegister("polygon", func() Shape { return &Polygon{} })
+	Default.MustRegister("triangle", func() Shape { return &Triangle{} })
+}
+
+// Register adds a shape to the Default registry.
+func Register(kind string, ctor func() Shape) error {
+	return Default.Register(kind, ctor)
+}
+
+// Register adds a shape under kind. ctor returns a new zero shape, which
+// must be a pointer so it can be decoded into.
+func (r *Registry) Register(kind string, ctor func() Shape) error {
+	if kind == "" || ctor == nil {
+		return errors.New("shapes: kind and constructor must be set")
+	}
+	t := reflect.TypeOf(ctor())
+	if t == nil || t.Kind() != reflect.Pointer {
+		return fmt.Errorf("shapes: %s constructor must return a pointer, got %v", kind, t)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ctors[kind]; ok {
+		return fmt.Errorf("%w: kind %q", ErrDuplicate, kind)
+	}
+	if old, ok := r.kinds[t]; ok {
+		return fmt.Errorf("%w: %s as %q", ErrDuplicate, t, old)
+	}
+	r.ctors[kind] = ctor
+	r.kinds[t] = kind
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry) MustRegister(kind string, ctor func() Shape) {
+	if err := r.Register(kind, ctor); err != nil {
+		panic(err)
+	}
+}
+
+// New returns a new zero shape of the given kind.
+func (r *Registry) New(kind string) (Shape, error) {
+	r.mu.RLock()
+	ctor, ok := r.ctors[kind]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &factory.UnknownNameError{Name: kind, Suggestions: factory.Suggest(kind, r.Kinds())}
+	}
+	return ctor(), nil
+}
+
+// Kind returns the name s is registered under.
+func (r *Registry) Kind(s Shape) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kind, ok := r.kinds[reflect.TypeOf(s)]
+	if !ok {
+		return "", fmt.Errorf("shapes: %T is not registered", s)
+	}
+	return kind, nil
+}
+
+// Kinds returns the registered kinds in sorted order.
+func (r *Registry) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]string, 0, len(r.ctors))
+	for k := range r.ctors {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
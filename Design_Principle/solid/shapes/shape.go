@@ -0,0 +1,139 @@
+// Package shapes builds out the Open/Closed example of solid.md. Code that
+// works with the Shape interface, such as TotalArea or the JSON encoding of
+// a List, does not change when a new shape is registered.
+package shapes
+
+import (
+	"fmt"
+	"math"
+)
+
+// Point is a position in the plane.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Box is an axis aligned rectangle from Min to Max.
+type Box struct {
+	Min Point `json:"min"`
+	Max Point `json:"max"`
+}
+
+// Width returns the extent of b along the x axis.
+func (b Box) Width() float64 { return b.Max.X - b.Min.X }
+
+// Height returns the extent of b along the y axis.
+func (b Box) Height() float64 { return b.Max.Y - b.Min.Y }
+
+// Union returns the smallest box containing b and o.
+func (b Box) Union(o Box) Box {
+	return Box{
+		Min: Point{math.Min(b.Min.X, o.Min.X), math.Min(b.Min.Y, o.Min.Y)},
+		Max: Point{math.Max(b.Max.X, o.Max.X), math.Max(b.Max.Y, o.Max.Y)},
+	}
+}
+
+// Contains reports whether p lies inside b or on its edge.
+func (b Box) Contains(p Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// Shape is a closed figure in the plane. Points on the edge count as
+// contained.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	Bounds() Box
+	Contains(p Point) bool
+}
+
+// Validator is implemented by shapes that can be invalid, such as a circle
+// with a negative radius. Decoding a List validates every shape.
+type Validator interface {
+	Validate() error
+}
+
+// TotalArea returns the sum of the areas of shapes.
+func TotalArea(shapes ...Shape) float64 {
+	total := 0.0
+	for _, s := range shapes {
+		total += s.Area()
+	}
+	return total
+}
+
+// Bounds returns the box containing all shapes.
+func Bounds(shapes ...Shape) Box {
+	if len(shapes) == 0 {
+		return Box{}
+	}
+	b := shapes[0].Bounds()
+	for _, s := range shapes[1:] {
+		b = b.Union(s.Bounds())
+	}
+	return b
+}
+
+// Rectangle is an axis aligned rectangle with its lower left corner at
+// Origin.
+type Rectangle struct {
+	Origin Point   `json:"origin"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r *Rectangle) Area() float64 {
+	return r.Width * r.Height
+}
+
+func (r *Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+func (r *Rectangle) Bounds() Box {
+	return Box{Min: r.Origin, Max: Point{r.Origin.X + r.Width, r.Origin.Y + r.Height}}
+}
+
+func (r *Rectangle) Contains(p Point) bool {
+	return r.Bounds().Contains(p)
+}
+
+func (r *Rectangle) Validate() error {
+	if r.Width < 0 || r.Height < 0 {
+		return fmt.Errorf("rectangle size %gx%g is negative", r.Width, r.Height)
+	}
+	return nil
+}
+
+// Circle is a circle around Center.
+type Circle struct {
+	Center Point   `json:"center"`
+	Radius float64 `json:"radius"`
+}
+
+func (c *Circle) Area() float64 {
+	return math.Pi * math.Pow(c.Radius, 2)
+}
+
+func (c *Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+func (c *Circle) Bounds() Box {
+	return Box{
+		Min: Point{c.Center.X - c.Radius, c.Center.Y - c.Radius},
+		Max: Point{c.Center.X + c.Radius, c.Center.Y + c.Radius},
+	}
+}
+
+func (c *Circle) Contains(p Point) bool {
+	return math.Hypot(p.X-c.Center.X, p.Y-c.Center.Y) <= c.Radius
+}
+
+func (c *Circle) Validate() error {
+	if c.Radius < 0 {
+		return fmt.Errorf("circle radius %g is negative", c.Radius)
+	}
+	return nil
+}
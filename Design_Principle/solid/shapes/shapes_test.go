@@ -0,0 +1,170 @@
+package shapes
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/factory"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMeasurements(t *testing.T) {
+	tests := []struct {
+		name      string
+		shape     Shape
+		area      float64
+		perimeter float64
+		bounds    Box
+	}{
+		{"rectangle", &Rectangle{Origin: Point{1, 1}, Width: 4, Height: 2}, 8, 12, Box{Point{1, 1}, Point{5, 3}}},
+		{"circle", &Circle{Center: Point{0, 0}, Radius: 1}, math.Pi, 2 * math.Pi, Box{Point{-1, -1}, Point{1, 1}}},
+		{"triangle", &Triangle{A: Point{0, 0}, B: Point{3, 0}, C: Point{0, 4}}, 6, 12, Box{Point{0, 0}, Point{3, 4}}},
+		{"polygon", &Polygon{Points: []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}, 4, 8, Box{Point{0, 0}, Point{2, 2}}},
+	}
+	for _, tt := range tests {
+		if a := tt.shape.Area(); !near(a, tt.area) {
+			t.Errorf("%s area = %g, want %g", tt.name, a, tt.area)
+		}
+		if p := tt.shape.Perimeter(); !near(p, tt.perimeter) {
+			t.Errorf("%s perimeter = %g, want %g", tt.name, p, tt.perimeter)
+		}
+		if b := tt.shape.Bounds(); b != tt.bounds {
+			t.Errorf("%s bounds = %+v, want %+v", tt.name, b, tt.bounds)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	// An L shaped polygon, so the notch is inside its bounds but outside it.
+	l := &Polygon{Points: []Point{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}}}
+	tests := []struct {
+		shape Shape
+		p     Point
+		want  bool
+	}{
+		{l, Point{0.5, 3}, true},
+		{l, Point{3, 0.5}, true},
+		{l, Point{3, 3}, false},
+		{l, Point{2, 1}, true}, // on an edge
+		{l, Point{0, 0}, true}, // on a vertex
+		{&Circle{Radius: 1}, Point{1, 0}, true},
+		{&Circle{Radius: 1}, Point{1, 1}, false},
+		{&Rectangle{Width: 2, Height: 2}, Point{2, 2}, true},
+		{&Triangle{A: Point{0, 0}, B: Point{4, 0}, C: Point{0, 4}}, Point{3, 3}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.shape.Contains(tt.p); got != tt.want {
+			t.Errorf("%T%+v contains %+v = %v, want %v", tt.shape, tt.shape, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestTotalAreaAndBounds(t *testing.T) {
+	list := []Shape{&Rectangle{Width: 2, Height: 3}, &Rectangle{Origin: Point{5, 5}, Width: 1, Height: 1}}
+	if a := TotalArea(list...); a != 7 {
+		t.Errorf("TotalArea = %g, want 7", a)
+	}
+	if b := Bounds(list...); b != (Box{Point{0, 0}, Point{6, 6}}) {
+		t.Errorf("Bounds = %+v", b)
+	}
+	if b := Bounds(); b != (Box{}) {
+		t.Errorf("Bounds() = %+v, want the zero box", b)
+	}
+}
+
+func TestListJSONRoundTrip(t *testing.T) {
+	in := List{
+		&Circle{Center: Point{1, 2}, Radius: 3},
+		&Rectangle{Width: 1, Height: 2},
+		&Triangle{A: Point{0, 0}, B: Point{1, 0}, C: Point{0, 1}},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), `[{"type":"circle","center":{"x":1,"y":2},"radius":3}`) {
+		t.Errorf("encoded %s", data)
+	}
+	var out List
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != len(in) || TotalArea(out...) != TotalArea(in...) {
+		t.Errorf("round trip gave %+v", out)
+	}
+}
+
+func TestListJSONErrors(t *testing.T) {
+	tests := map[string]string{
+		`[{"radius":1}]`:                   "missing type",
+		`[{"type":"circel","radius":1}]`:   `did you mean "circle"?`,
+		`[{"type":"circle","radius":-1}]`:  "negative",
+		`[{"type":"polygon","points":[]}]`: "at least 3 points",
+		`{"type":"circle"}`:                "cannot unmarshal",
+	}
+	for doc, want := range tests {
+		var l List
+		err := json.Unmarshal([]byte(doc), &l)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Unmarshal(%s) = %v, want an error containing %q", doc, err, want)
+		}
+	}
+}
+
+// square is a shape from outside the package.
+type square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *square) Area() float64         { return s.Side * s.Side }
+func (s *square) Perimeter() float64    { return 4 * s.Side }
+func (s *square) Bounds() Box           { return Box{Max: Point{s.Side, s.Side}} }
+func (s *square) Contains(p Point) bool { return s.Bounds().Contains(p) }
+
+func TestRegistryPlugsInNewShapes(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister("square", func() Shape { return &square{} })
+
+	data, err := r.Marshal([]Shape{&square{Side: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[{"type":"square","side":2}]` {
+		t.Errorf("encoded %s", data)
+	}
+	shapes, err := r.Unmarshal(data)
+	if err != nil || len(shapes) != 1 || shapes[0].Area() != 4 {
+		t.Errorf("Unmarshal = %v, %v", shapes, err)
+	}
+
+	if _, err := r.Marshal([]Shape{&Circle{}}); err == nil {
+		t.Error("Marshal accepted an unregistered shape")
+	}
+	var unknown *factory.UnknownNameError
+	if _, err := r.New("circle"); !errors.As(err, &unknown) {
+		t.Errorf("New(circle) = %v, want *UnknownNameError", err)
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister("square", func() Shape { return &square{} })
+	if err := r.Register("square", func() Shape { return &Circle{} }); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate kind = %v, want ErrDuplicate", err)
+	}
+	if err := r.Register("box", func() Shape { return &square{} }); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate type = %v, want ErrDuplicate", err)
+	}
+	if err := r.Register("nothing", nil); err == nil {
+		t.Error("Register accepted a nil constructor")
+	}
+	if err := r.Register("nil", func() Shape { return nil }); err == nil {
+		t.Error("Register accepted a constructor returning nil")
+	}
+}
@@ -0,0 +1,155 @@
+package userrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore is a UserRepository in a JSON file. Every change rewrites the
+// file through a temporary file and a rename, so a crash never leaves it
+// half written. Stores in one process sharing a path share a lock; separate
+// processes must not write the same file.
+type FileStore struct {
+	path string
+	mu   *sync.Mutex
+}
+
+var fileLocks sync.Map // absolute path -> *sync.Mutex
+
+// NewFileStore returns a store in the file at path, which is created on the
+// first save.
+func NewFileStore(path string) (*FileStore, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	mu, _ := fileLocks.LoadOrStore(abs, &sync.Mutex{})
+	return &FileStore{path: abs, mu: mu.(*sync.Mutex)}, nil
+}
+
+func (f *FileStore) Save(ctx context.Context, u *User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	users, err := f.load()
+	if err != nil {
+		return err
+	}
+	next, err := checkSave(u, users)
+	if err != nil {
+		return err
+	}
+	stored := *u
+	stored.Version = next
+	users[u.ID] = stored
+	if err := f.store(users); err != nil {
+		return err
+	}
+	u.Version = next
+	return nil
+}
+
+func (f *FileStore) Get(ctx context.Context, id string) (*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	users, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	u, ok := users[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return &u, nil
+}
+
+func (f *FileStore) List(ctx context.Context) ([]*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	users, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return sorted(users), nil
+}
+
+func (f *FileStore) Delete(ctx context.Context, id string, version int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	users, err := f.load()
+	if err != nil {
+		return err
+	}
+	if err := checkDelete(id, version, users); err != nil {
+		return err
+	}
+	delete(users, id)
+	return f.store(users)
+}
+
+func (f *FileStore) load() (map[string]User, error) {
+	users := make(map[string]User)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return users, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("userrepo: %w", err)
+	}
+	var list []User
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("userrepo: %s: %w", f.path, err)
+	}
+	for _, u := range list {
+		users[u.ID] = u
+	}
+	return users, nil
+}
+
+func (f *FileStore) store(users map[string]User) error {
+	list := make([]User, 0, len(users))
+	for _, u := range sorted(users) {
+		list = append(list, *u)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package userrepo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a UserRepository in memory.
+type MemoryStore struct {
+	mu    sync.Mutex
+	users map[string]User
+}
+
+// NewMemoryStore returns an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: make(map[string]User)}
+}
+
+func (m *MemoryStore) Save(ctx context.Context, u *User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next, err := checkSave(u, m.users)
+	if err != nil {
+		return err
+	}
+	stored := *u
+	stored.Version = next
+	m.users[u.ID] = stored
+	u.Version = next
+	return nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, id string) (*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return &u, nil
+}
+
+func (m *MemoryStore) List(ctx context.Context) ([]*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sorted(m.users), nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string, version int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := checkDelete(id, version, m.users); err != nil {
+		return err
+	}
+	delete(m.users, id)
+	return nil
+}
+
+// checkSave returns the version u gets when saved over users, or the
+// reason it cannot be saved.
+func checkSave(u *User, users map[string]User) (int64, error) {
+	if u.ID == "" {
+		return 0, fmt.Errorf("%w: empty ID", ErrInvalid)
+	}
+	stored, ok := users[u.ID]
+	return nextVersion(u, stored.Version, ok)
+}
+
+func nextVersion(u *User, stored int64, exists bool) (int64, error) {
+	switch {
+	case u.Version == 0 && exists:
+		return 0, fmt.Errorf("%w: user %s already exists", ErrConflict, u.ID)
+	case u.Version != 0 && !exists:
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, u.ID)
+	case u.Version != stored:
+		return 0, fmt.Errorf("%w: user %s is at version %d, not %d", ErrConflict, u.ID, stored, u.Version)
+	}
+	return u.Version + 1, nil
+}
+
+func checkDelete(id string, version int64, users map[string]User) error {
+	stored, ok := users[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if stored.Version != version {
+		return fmt.Errorf("%w: user %s is at version %d, not %d", ErrConflict, id, stored.Version, version)
+	}
+	return nil
+}
+
+func sorted(users map[string]User) []*User {
+	list := make([]*User, 0, len(users))
+	for _, u := range users {
+		u := u
+		list = append(list, &u)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
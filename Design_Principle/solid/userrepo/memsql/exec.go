@@ -0,0 +1,237 @@
+package memsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type statement interface {
+	exec(db *database, args []driver.Value) (int64, error)
+}
+
+type createStmt struct {
+	table       string
+	ifNotExists bool
+	cols        []column
+}
+
+type insertStmt struct {
+	table string
+	cols  []string
+	vals  []*expr
+}
+
+type selectStmt struct {
+	table   string
+	cols    []string
+	where   []condition
+	orderBy string
+	desc    bool
+}
+
+type updateStmt struct {
+	table string
+	sets  []assignment
+	where []condition
+}
+
+type deleteStmt struct {
+	table string
+	where []condition
+}
+
+type condition struct {
+	col   string
+	value *expr
+}
+
+type assignment struct {
+	col   string
+	value *expr
+}
+
+func lookup(db *database, name string) (*table, error) {
+	t, ok := db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("memsql: no such table: %s", name)
+	}
+	return t, nil
+}
+
+// matches reports whether row satisfies every condition.
+func matches(t *table, row []driver.Value, where []condition, args []driver.Value) (bool, error) {
+	for _, c := range where {
+		i, err := t.index(c.col)
+		if err != nil {
+			return false, err
+		}
+		v, err := c.value.eval(t, row, args)
+		if err != nil {
+			return false, err
+		}
+		if row[i] == nil || v == nil || compare(row[i], v) != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// check enforces NOT NULL and PRIMARY KEY constraints for row, which is
+// stored at index at, or -1 for a new row.
+func (t *table) check(row []driver.Value, at int) error {
+	for i, c := range t.cols {
+		if (c.notNull || c.primary) && row[i] == nil {
+			return fmt.Errorf("memsql: NOT NULL constraint failed: %s.%s", t.name, c.name)
+		}
+		if !c.primary {
+			continue
+		}
+		for j, other := range t.rows {
+			if j != at && compare(other[i], row[i]) == 0 {
+				return fmt.Errorf("memsql: UNIQUE constraint failed: %s.%s", t.name, c.name)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *createStmt) exec(db *database, args []driver.Value) (int64, error) {
+	if _, ok := db.tables[s.table]; ok {
+		if s.ifNotExists {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("memsql: table %s already exists", s.table)
+	}
+	db.tables[s.table] = &table{name: s.table, cols: s.cols}
+	return 0, nil
+}
+
+func (s *insertStmt) exec(db *database, args []driver.Value) (int64, error) {
+	t, err := lookup(db, s.table)
+	if err != nil {
+		return 0, err
+	}
+	row := make([]driver.Value, len(t.cols))
+	for k, col := range s.cols {
+		i, err := t.index(col)
+		if err != nil {
+			return 0, err
+		}
+		if row[i], err = s.vals[k].eval(t, nil, args); err != nil {
+			return 0, err
+		}
+	}
+	if err := t.check(row, -1); err != nil {
+		return 0, err
+	}
+	t.rows = append(t.rows, row)
+	return 1, nil
+}
+
+func (s *selectStmt) exec(db *database, args []driver.Value) (int64, error) {
+	return 0, fmt.Errorf("memsql: use Query for SELECT")
+}
+
+func (s *selectStmt) query(db *database, args []driver.Value) (*rows, error) {
+	t, err := lookup(db, s.table)
+	if err != nil {
+		return nil, err
+	}
+	names := s.cols
+	if names == nil {
+		for _, c := range t.cols {
+			names = append(names, c.name)
+		}
+	}
+	idx := make([]int, len(names))
+	for k, name := range names {
+		if idx[k], err = t.index(name); err != nil {
+			return nil, err
+		}
+	}
+	var matched [][]driver.Value
+	for _, row := range t.rows {
+		ok, err := matches(t, row, s.where, args)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, row)
+		}
+	}
+	if s.orderBy != "" {
+		i, err := t.index(s.orderBy)
+		if err != nil {
+			return nil, err
+		}
+		sortRows(matched, i, s.desc)
+	}
+	r := &rows{cols: names}
+	for _, row := range matched {
+		out := make([]driver.Value, len(idx))
+		for k, i := range idx {
+			out[k] = row[i]
+		}
+		r.data = append(r.data, out)
+	}
+	return r, nil
+}
+
+func (s *updateStmt) exec(db *database, args []driver.Value) (int64, error) {
+	t, err := lookup(db, s.table)
+	if err != nil {
+		return 0, err
+	}
+	// The rows are updated in a copy so that a failing statement changes
+	// nothing, as in SQLite.
+	next := &table{name: t.name, cols: t.cols, rows: append([][]driver.Value(nil), t.rows...)}
+	var n int64
+	for at, row := range t.rows {
+		ok, err := matches(t, row, s.where, args)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		updated := append([]driver.Value(nil), row...)
+		for _, a := range s.sets {
+			i, err := t.index(a.col)
+			if err != nil {
+				return 0, err
+			}
+			if updated[i], err = a.value.eval(t, row, args); err != nil {
+				return 0, err
+			}
+		}
+		next.rows[at] = updated
+		if err := next.check(updated, at); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	t.rows = next.rows
+	return n, nil
+}
+
+func (s *deleteStmt) exec(db *database, args []driver.Value) (int64, error) {
+	t, err := lookup(db, s.table)
+	if err != nil {
+		return 0, err
+	}
+	kept := t.rows[:0:0]
+	var n int64
+	for _, row := range t.rows {
+		ok, err := matches(t, row, s.where, args)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			n++
+		} else {
+			kept = append(kept, row)
+		}
+	}
+	t.rows = kept
+	return n, nil
+}
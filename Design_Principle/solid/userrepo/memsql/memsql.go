@@ -0,0 +1,269 @@
+// Package memsql is an in-memory database/sql driver for tests. It stands in
+// for SQLite and understands the small SQLite compatible subset that the
+// stores in this repository use:
+//
+//	CREATE TABLE [IF NOT EXISTS] t (col type [PRIMARY KEY] [NOT NULL], ...)
+//	INSERT INTO t (col, ...) VALUES (expr, ...)
+//	SELECT * | col, ... FROM t [WHERE col = expr [AND ...]] [ORDER BY col [ASC | DESC]]
+//	UPDATE t SET col = expr, ... [WHERE ...]
+//	DELETE FROM t [WHERE ...]
+//
+// where expr is a ? placeholder, a number, a 'string', NULL, a column or
+// expr + expr. Column types are not enforced, as in SQLite. Connections
+// opened with the same data source name share a database:
+//
+//	db, err := sql.Open("memsql", "users")
+//
+// A transaction holds the database exclusively until it ends.
+package memsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+func init() {
+	sql.Register("memsql", Driver{})
+}
+
+// Driver is the memsql driver.
+type Driver struct{}
+
+var (
+	databasesMu sync.Mutex
+	databases   = map[string]*database{}
+)
+
+// Open returns a connection to the database named name, creating it if
+// needed.
+func (Driver) Open(name string) (driver.Conn, error) {
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+	db, ok := databases[name]
+	if !ok {
+		db = &database{tables: make(map[string]*table)}
+		databases[name] = db
+	}
+	return &conn{db: db}, nil
+}
+
+// Drop deletes the database named name. Open connections keep using it.
+func Drop(name string) {
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+	delete(databases, name)
+}
+
+type database struct {
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+type table struct {
+	name string
+	cols []column
+	rows [][]driver.Value
+}
+
+type column struct {
+	name             string
+	primary, notNull bool
+}
+
+func (t *table) index(col string) (int, error) {
+	for i, c := range t.cols {
+		if strings.EqualFold(c.name, col) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("memsql: table %s has no column %s", t.name, col)
+}
+
+func (t *table) clone() *table {
+	c := &table{name: t.name, cols: t.cols, rows: make([][]driver.Value, len(t.rows))}
+	for i, r := range t.rows {
+		c.rows[i] = append([]driver.Value(nil), r...)
+	}
+	return c
+}
+
+type conn struct {
+	db *database
+	tx *tx
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	st, n, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{conn: c, st: st, params: n}, nil
+}
+
+func (c *conn) Close() error {
+	if c.tx != nil {
+		return c.tx.Rollback()
+	}
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("memsql: transaction already open")
+	}
+	c.db.mu.Lock()
+	snapshot := make(map[string]*table, len(c.db.tables))
+	for name, t := range c.db.tables {
+		snapshot[name] = t.clone()
+	}
+	c.tx = &tx{conn: c, snapshot: snapshot}
+	return c.tx, nil
+}
+
+type tx struct {
+	conn     *conn
+	snapshot map[string]*table
+}
+
+func (t *tx) Commit() error {
+	t.conn.tx = nil
+	t.conn.db.mu.Unlock()
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	t.conn.db.tables = t.snapshot
+	t.conn.tx = nil
+	t.conn.db.mu.Unlock()
+	return nil
+}
+
+type stmt struct {
+	conn   *conn
+	st     statement
+	params int
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return s.params }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	var n int64
+	err := s.conn.run(func(db *database) (err error) {
+		n, err = s.st.exec(db, args)
+		return err
+	})
+	return driver.RowsAffected(n), err
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	sel, ok := s.st.(*selectStmt)
+	if !ok {
+		return nil, errors.New("memsql: Query needs a SELECT statement")
+	}
+	var r *rows
+	err := s.conn.run(func(db *database) (err error) {
+		r, err = sel.query(db, args)
+		return err
+	})
+	return r, err
+}
+
+// run calls fn with the database locked, unless the connection's own
+// transaction already holds the lock.
+func (c *conn) run(fn func(*database) error) error {
+	if c.tx == nil {
+		c.db.mu.Lock()
+		defer c.db.mu.Unlock()
+	}
+	return fn(c.db)
+}
+
+type rows struct {
+	cols []string
+	data [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.cols }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.data[0])
+	r.data = r.data[1:]
+	return nil
+}
+
+// compare orders values: NULL first, then numbers, then strings.
+func compare(a, b driver.Value) int {
+	ra, rb := rank(a), rank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch ra {
+	case 1:
+		fa, fb := toFloat(a), toFloat(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case 2:
+		return strings.Compare(toString(a), toString(b))
+	}
+	return 0
+}
+
+func rank(v driver.Value) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case int64, float64, bool:
+		return 1
+	}
+	return 2
+}
+
+func toFloat(v driver.Value) float64 {
+	switch v := v.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+func toString(v driver.Value) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
+
+func sortRows(data [][]driver.Value, col int, desc bool) {
+	sort.SliceStable(data, func(i, j int) bool {
+		c := compare(data[i][col], data[j][col])
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
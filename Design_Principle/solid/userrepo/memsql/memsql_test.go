@@ -0,0 +1,155 @@
+package memsql
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("memsql", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		Drop(t.Name())
+	})
+	exec(t, db, `CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL, n INTEGER)`)
+	return db
+}
+
+func exec(t *testing.T, db *sql.DB, query string, args ...any) int64 {
+	t.Helper()
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	n, _ := res.RowsAffected()
+	return n
+}
+
+func names(t *testing.T, db *sql.DB, query string, args ...any) string {
+	t.Helper()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestQueries(t *testing.T) {
+	db := openDB(t)
+	exec(t, db, `INSERT INTO items (id, name, n) VALUES (?, ?, ?)`, 2, "b", 20)
+	exec(t, db, `INSERT INTO items (id, name, n) VALUES (1, 'it''s', 10)`)
+	exec(t, db, `INSERT INTO items (id, name) VALUES (3, 'c')`)
+
+	if got := names(t, db, `SELECT name FROM items ORDER BY id`); got != "it's,b,c" {
+		t.Errorf("ORDER BY id = %s", got)
+	}
+	if got := names(t, db, `SELECT name FROM items ORDER BY n DESC`); got != "b,it's,c" {
+		t.Errorf("ORDER BY n DESC = %s", got)
+	}
+	if got := names(t, db, `SELECT name FROM items WHERE id = ? AND n = ?`, 2, 20); got != "b" {
+		t.Errorf("WHERE = %s", got)
+	}
+	// NULL never equals anything.
+	if got := names(t, db, `SELECT name FROM items WHERE n = NULL`); got != "" {
+		t.Errorf("WHERE n = NULL = %s", got)
+	}
+
+	if n := exec(t, db, `UPDATE items SET n = n + 1 WHERE id = ?`, 2); n != 1 {
+		t.Errorf("UPDATE affected %d rows", n)
+	}
+	var n int64
+	if err := db.QueryRow(`SELECT n FROM items WHERE id = 2`).Scan(&n); err != nil || n != 21 {
+		t.Errorf("n = %d, %v, want 21", n, err)
+	}
+	if n := exec(t, db, `DELETE FROM items WHERE name = 'c'`); n != 1 {
+		t.Errorf("DELETE affected %d rows", n)
+	}
+	if got := names(t, db, `SELECT name FROM items ORDER BY id`); got != "it's,b" {
+		t.Errorf("after DELETE = %s", got)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	db := openDB(t)
+	exec(t, db, `INSERT INTO items (id, name) VALUES (1, 'a')`)
+	exec(t, db, `INSERT INTO items (id, name) VALUES (2, 'b')`)
+	for _, q := range []string{
+		`INSERT INTO items (id, name) VALUES (1, 'again')`,
+		`INSERT INTO items (id) VALUES (3)`,
+		`UPDATE items SET id = 2 WHERE id = 1`,
+		`CREATE TABLE items (id INTEGER)`,
+		`SELECT name FROM nothing`,
+		`SELECT nope FROM items`,
+	} {
+		if _, err := db.Exec(q); err == nil {
+			t.Errorf("%s succeeded", q)
+		}
+	}
+	exec(t, db, `CREATE TABLE IF NOT EXISTS items (id INTEGER)`)
+}
+
+func TestStatementIsAtomic(t *testing.T) {
+	db := openDB(t)
+	exec(t, db, `INSERT INTO items (id, name, n) VALUES (1, 'a', 1)`)
+	exec(t, db, `INSERT INTO items (id, name, n) VALUES (2, 'b', 'x')`)
+	if _, err := db.Exec(`UPDATE items SET n = n + 1`); err == nil {
+		t.Fatal("adding to a string succeeded")
+	}
+	var n int64
+	if err := db.QueryRow(`SELECT n FROM items WHERE id = 1`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("n = %d, %v; a failed UPDATE changed a row", n, err)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	db := openDB(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`INSERT INTO items (id, name) VALUES (1, 'a')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(t, db, `SELECT name FROM items`); got != "" {
+		t.Errorf("after rollback = %s", got)
+	}
+
+	tx, _ = db.Begin()
+	tx.Exec(`INSERT INTO items (id, name) VALUES (1, 'a')`)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(t, db, `SELECT name FROM items`); got != "a" {
+		t.Errorf("after commit = %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, q := range []string{
+		`SELECT name FROM`,
+		`INSERT INTO items (id) VALUES ('open`,
+		`DROP TABLE items`,
+		`SELECT name FROM items WHERE id > 1`,
+	} {
+		if _, _, err := parse(q); err == nil {
+			t.Errorf("parse(%s) succeeded", q)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package memsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tIdent tokenKind = iota
+	tNumber
+	tString
+	tParam
+	tPunct
+	tEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func lex(query string) ([]token, error) {
+	var toks []token
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{tIdent, string(rs[i:j])})
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{tNumber, string(rs[i:j])})
+			i = j
+		case r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; ; j++ {
+				if j >= len(rs) {
+					return nil, fmt.Errorf("memsql: unterminated string in %q", query)
+				}
+				if rs[j] == '\'' {
+					if j+1 < len(rs) && rs[j+1] == '\'' {
+						b.WriteRune('\'')
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteRune(rs[j])
+			}
+			toks = append(toks, token{tString, b.String()})
+			i = j + 1
+		case r == '?':
+			toks = append(toks, token{tParam, "?"})
+			i++
+		case strings.ContainsRune("(),=+*;", r):
+			toks = append(toks, token{tPunct, string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("memsql: unexpected %q in %q", r, query)
+		}
+	}
+	return append(toks, token{tEOF, ""}), nil
+}
+
+type parser struct {
+	query  string
+	toks   []token
+	pos    int
+	params int
+}
+
+func parse(query string) (statement, int, error) {
+	toks, err := lex(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	p := &parser{query: query, toks: toks}
+	st, err := p.statement()
+	if err != nil {
+		return nil, 0, err
+	}
+	p.accept(";")
+	if p.peek().kind != tEOF {
+		return nil, 0, p.errorf("unexpected %q", p.peek().text)
+	}
+	return st, p.params, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("memsql: %s in %q", fmt.Sprintf(format, args...), p.query)
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the keyword or punctuation s.
+func (p *parser) accept(s string) bool {
+	t := p.peek()
+	if (t.kind == tIdent || t.kind == tPunct) && strings.EqualFold(t.text, s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(words ...string) error {
+	for _, w := range words {
+		if !p.accept(w) {
+			return p.errorf("expected %s, found %q", w, p.peek().text)
+		}
+	}
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.next()
+	if t.kind != tIdent {
+		return "", p.errorf("expected a name, found %q", t.text)
+	}
+	return t.text, nil
+}
+
+// list parses a comma separated list with item.
+func (p *parser) list(item func() error) error {
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) statement() (statement, error) {
+	switch {
+	case p.accept("CREATE"):
+		return p.create()
+	case p.accept("INSERT"):
+		return p.insert()
+	case p.accept("SELECT"):
+		return p.selectStmt()
+	case p.accept("UPDATE"):
+		return p.update()
+	case p.accept("DELETE"):
+		return p.delete()
+	}
+	return nil, p.errorf("unsupported statement")
+}
+
+func (p *parser) create() (statement, error) {
+	if err := p.expect("TABLE"); err != nil {
+		return nil, err
+	}
+	st := &createStmt{}
+	if p.accept("IF") {
+		if err := p.expect("NOT", "EXISTS"); err != nil {
+			return nil, err
+		}
+		st.ifNotExists = true
+	}
+	var err error
+	if st.table, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	err = p.list(func() error {
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		c := column{name: name}
+		for {
+			switch {
+			case p.accept("PRIMARY"):
+				if err := p.expect("KEY"); err != nil {
+					return err
+				}
+				c.primary = true
+			case p.accept("NOT"):
+				if err := p.expect("NULL"); err != nil {
+					return err
+				}
+				c.notNull = true
+			case p.peek().kind == tIdent:
+				p.next() // the type, which is not enforced
+			default:
+				st.cols = append(st.cols, c)
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return st, p.expect(")")
+}
+
+func (p *parser) insert() (statement, error) {
+	if err := p.expect("INTO"); err != nil {
+		return nil, err
+	}
+	st := &insertStmt{}
+	var err error
+	if st.table, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	err = p.list(func() error {
+		c, err := p.ident()
+		st.cols = append(st.cols, c)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")", "VALUES", "("); err != nil {
+		return nil, err
+	}
+	err = p.list(func() error {
+		e, err := p.expr()
+		st.vals = append(st.vals, e)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(st.cols) != len(st.vals) {
+		return nil, p.errorf("%d columns but %d values", len(st.cols), len(st.vals))
+	}
+	return st, nil
+}
+
+func (p *parser) selectStmt() (statement, error) {
+	st := &selectStmt{}
+	if !p.accept("*") {
+		err := p.list(func() error {
+			c, err := p.ident()
+			st.cols = append(st.cols, c)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	var err error
+	if st.table, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if st.where, err = p.where(); err != nil {
+		return nil, err
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		if st.orderBy, err = p.ident(); err != nil {
+			return nil, err
+		}
+		if p.accept("DESC") {
+			st.desc = true
+		} else {
+			p.accept("ASC")
+		}
+	}
+	return st, nil
+}
+
+func (p *parser) update() (statement, error) {
+	st := &updateStmt{}
+	var err error
+	if st.table, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("SET"); err != nil {
+		return nil, err
+	}
+	err = p.list(func() error {
+		c, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("="); err != nil {
+			return err
+		}
+		e, err := p.expr()
+		st.sets = append(st.sets, assignment{col: c, value: e})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	st.where, err = p.where()
+	return st, err
+}
+
+func (p *parser) delete() (statement, error) {
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	st := &deleteStmt{}
+	var err error
+	if st.table, err = p.ident(); err != nil {
+		return nil, err
+	}
+	st.where, err = p.where()
+	return st, err
+}
+
+func (p *parser) where() ([]condition, error) {
+	if !p.accept("WHERE") {
+		return nil, nil
+	}
+	var conds []condition
+	for {
+		c, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, condition{col: c, value: e})
+		if !p.accept("AND") {
+			return conds, nil
+		}
+	}
+}
+
+func (p *parser) expr() (*expr, error) {
+	t := p.next()
+	e := &expr{param: -1}
+	switch {
+	case t.kind == tParam:
+		e.param = p.params
+		p.params++
+	case t.kind == tNumber:
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			e.lit = i
+		} else if f, err := strconv.ParseFloat(t.text, 64); err == nil {
+			e.lit = f
+		} else {
+			return nil, p.errorf("bad number %q", t.text)
+		}
+	case t.kind == tString:
+		e.lit = t.text
+	case t.kind == tIdent && strings.EqualFold(t.text, "NULL"):
+	case t.kind == tIdent:
+		e.col = t.text
+	default:
+		return nil, p.errorf("expected a value, found %q", t.text)
+	}
+	if p.accept("+") {
+		var err error
+		if e.plus, err = p.expr(); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// expr is a placeholder, literal or column, optionally plus another expr.
+type expr struct {
+	param int
+	lit   driver.Value
+	col   string
+	plus  *expr
+}
+
+func (e *expr) eval(t *table, row []driver.Value, args []driver.Value) (driver.Value, error) {
+	var v driver.Value
+	switch {
+	case e.param >= 0:
+		if e.param >= len(args) {
+			return nil, fmt.Errorf("memsql: missing argument %d", e.param+1)
+		}
+		v = args[e.param]
+	case e.col != "":
+		if row == nil {
+			return nil, fmt.Errorf("memsql: column %s is not allowed here", e.col)
+		}
+		i, err := t.index(e.col)
+		if err != nil {
+			return nil, err
+		}
+		v = row[i]
+	default:
+		v = e.lit
+	}
+	if e.plus == nil {
+		return v, nil
+	}
+	w, err := e.plus.eval(t, row, args)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case v == nil || w == nil:
+		return nil, nil
+	case rank(v) != 1 || rank(w) != 1:
+		return nil, fmt.Errorf("memsql: cannot add %v and %v", v, w)
+	}
+	a, aok := v.(int64)
+	b, bok := w.(int64)
+	if aok && bok {
+		return a + b, nil
+	}
+	return toFloat(v) + toFloat(w), nil
+}
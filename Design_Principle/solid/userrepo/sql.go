@@ -0,0 +1,139 @@
+package userrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SQLStore is a UserRepository in a SQL database. The queries use "?"
+// placeholders and standard SQL, as understood by SQLite and MySQL.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore returns a store using db. Call Migrate to create its table.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Migrate creates the users table if it does not exist.
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS users (
+		id         TEXT PRIMARY KEY,
+		first_name TEXT NOT NULL,
+		last_name  TEXT NOT NULL,
+		email      TEXT NOT NULL,
+		version    INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("userrepo: migrate: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Save(ctx context.Context, u *User) error {
+	if u.ID == "" {
+		return fmt.Errorf("%w: empty ID", ErrInvalid)
+	}
+	if u.Version == 0 {
+		_, err := s.db.ExecContext(ctx,
+			`INSERT INTO users (id, first_name, last_name, email, version) VALUES (?, ?, ?, ?, 1)`,
+			u.ID, u.FirstName, u.LastName, u.Email)
+		if err != nil {
+			// A primary key violation means the user exists; the error
+			// itself differs between drivers.
+			if _, verr := s.version(ctx, u.ID); verr == nil {
+				return fmt.Errorf("%w: user %s already exists", ErrConflict, u.ID)
+			}
+			return fmt.Errorf("userrepo: %w", err)
+		}
+		u.Version = 1
+		return nil
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE users SET first_name = ?, last_name = ?, email = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		u.FirstName, u.LastName, u.Email, u.ID, u.Version)
+	if err != nil {
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	if err := s.checkAffected(ctx, res, u.ID, u.Version); err != nil {
+		return err
+	}
+	u.Version++
+	return nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (*User, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, first_name, last_name, email, version FROM users WHERE id = ?`, id)
+	var u User
+	err := row.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("userrepo: %w", err)
+	}
+	return &u, nil
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]*User, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, first_name, last_name, email, version FROM users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("userrepo: %w", err)
+	}
+	defer rows.Close()
+	users := []*User{}
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.Version); err != nil {
+			return nil, fmt.Errorf("userrepo: %w", err)
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("userrepo: %w", err)
+	}
+	return users, nil
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string, version int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM users WHERE id = ? AND version = ?`, id, version)
+	if err != nil {
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	return s.checkAffected(ctx, res, id, version)
+}
+
+// checkAffected turns an update or delete that matched no row into
+// ErrNotFound or ErrConflict.
+func (s *SQLStore) checkAffected(ctx context.Context, res sql.Result, id string, version int64) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("userrepo: %w", err)
+	}
+	if n == 1 {
+		return nil
+	}
+	stored, err := s.version(ctx, id)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: user %s is at version %d, not %d", ErrConflict, id, stored, version)
+}
+
+func (s *SQLStore) version(ctx context.Context, id string) (int64, error) {
+	var v int64
+	err := s.db.QueryRowContext(ctx, `SELECT version FROM users WHERE id = ?`, id).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("userrepo: %w", err)
+	}
+	return v, nil
+}
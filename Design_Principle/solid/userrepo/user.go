@@ -0,0 +1,46 @@
+// Package userrepo is the storage half of the Single Responsibility example
+// in solid.md. User only manages user data; a UserRepository stores it, in
+// memory, in a file or in a SQL database.
+package userrepo
+
+import (
+	"context"
+	"errors"
+)
+
+// User is a stored user. Version is managed by the repository: it is 0
+// for a user that has never been saved and grows with every save.
+type User struct {
+	ID        string `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Version   int64  `json:"version"`
+}
+
+func (u *User) GetFullName() string {
+	return u.FirstName + " " + u.LastName
+}
+
+// Errors returned by every UserRepository.
+var (
+	ErrNotFound = errors.New("userrepo: user not found")
+	// ErrConflict means the user was changed or created by someone else
+	// since it was read. Get it again, reapply the change and save.
+	ErrConflict = errors.New("userrepo: version conflict")
+	ErrInvalid  = errors.New("userrepo: invalid user")
+)
+
+// UserRepository stores users with optimistic concurrency: a save or delete
+// only succeeds if the version it was given is still the stored one.
+type UserRepository interface {
+	// Save creates u if its Version is 0 and updates it otherwise. On
+	// success u.Version is set to the new version.
+	Save(ctx context.Context, u *User) error
+	// Get returns a copy of the user with the given ID.
+	Get(ctx context.Context, id string) (*User, error)
+	// List returns all users ordered by ID.
+	List(ctx context.Context) ([]*User, error)
+	// Delete removes the user if it is still at version.
+	Delete(ctx context.Context, id string, version int64) error
+}
@@ -0,0 +1,110 @@
+package userrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Principle/solid/userrepo"
+	"github.com/rnsasg/GO_Design/Design_Principle/solid/userrepo/memsql"
+	"github.com/rnsasg/GO_Design/Design_Principle/solid/userrepo/userrepotest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	userrepotest.Run(t, func(t *testing.T) userrepo.UserRepository {
+		return userrepo.NewMemoryStore()
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	userrepotest.Run(t, func(t *testing.T) userrepo.UserRepository {
+		f, err := userrepo.NewFileStore(filepath.Join(t.TempDir(), "users.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	})
+}
+
+func TestSQLStore(t *testing.T) {
+	userrepotest.Run(t, func(t *testing.T) userrepo.UserRepository {
+		return newSQLStore(t)
+	})
+}
+
+func newSQLStore(t *testing.T) *userrepo.SQLStore {
+	t.Helper()
+	db, err := sql.Open("memsql", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		memsql.Drop(t.Name())
+	})
+	s := userrepo.NewSQLStore(db)
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFileStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	first, _ := userrepo.NewFileStore(path)
+	mustSave(t, first, &userrepo.User{ID: "ann", FirstName: "Ann"})
+
+	second, _ := userrepo.NewFileStore(path)
+	if u := mustGet(t, second, "ann"); u.FirstName != "Ann" || u.Version != 1 {
+		t.Errorf("reopened store has %+v", u)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `"first_name": "Ann"`) {
+		t.Errorf("file = %s, %v", data, err)
+	}
+	if tmp, _ := filepath.Glob(path + ".*.tmp"); len(tmp) != 0 {
+		t.Errorf("temporary files left behind: %v", tmp)
+	}
+}
+
+func TestFileStoreCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := userrepo.NewFileStore(path)
+	if _, err := f.List(context.Background()); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("List = %v, want an error naming the file", err)
+	}
+	if err := f.Save(context.Background(), &userrepo.User{ID: "ann"}); err == nil {
+		t.Error("Save over a corrupt file succeeded")
+	}
+}
+
+func TestMigrateTwice(t *testing.T) {
+	s := newSQLStore(t)
+	mustSave(t, s, &userrepo.User{ID: "ann"})
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, s, "ann")
+}
+
+func mustSave(t *testing.T, r userrepo.UserRepository, u *userrepo.User) {
+	t.Helper()
+	if err := r.Save(context.Background(), u); err != nil {
+		t.Fatalf("Save(%s): %v", u.ID, err)
+	}
+}
+
+func mustGet(t *testing.T, r userrepo.UserRepository, id string) *userrepo.User {
+	t.Helper()
+	u, err := r.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	return u
+}
@@ -0,0 +1,214 @@
+// Package userrepotest is a conformance suite for userrepo.UserRepository
+// implementations.
+package userrepotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rnsasg/GO_Design/Design_Principle/solid/userrepo"
+)
+
+// Run checks that a UserRepository behaves as documented. newRepo must
+// return an empty repository; it is called once per check. Call it from
+// each backend's tests:
+//
+//	func TestMemoryStore(t *testing.T) {
+//		userrepotest.Run(t, func(t *testing.T) userrepo.UserRepository {
+//			return userrepo.NewMemoryStore()
+//		})
+//	}
+func Run(t *testing.T, newRepo func(t *testing.T) userrepo.UserRepository) {
+	ctx := context.Background()
+	ann := func() *userrepo.User {
+		return &userrepo.User{ID: "ann", FirstName: "Ann", LastName: "Lee", Email: "ann@example.com"}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		r := newRepo(t)
+		u := ann()
+		mustSave(t, r, u)
+		if u.Version != 1 {
+			t.Fatalf("version after create = %d, want 1", u.Version)
+		}
+		got := mustGet(t, r, "ann")
+		if *got != *u {
+			t.Fatalf("Get = %+v, want %+v", got, u)
+		}
+	})
+
+	t.Run("create existing", func(t *testing.T) {
+		r := newRepo(t)
+		mustSave(t, r, ann())
+		if err := r.Save(ctx, ann()); !errors.Is(err, userrepo.ErrConflict) {
+			t.Fatalf("second create: got %v, want userrepo.ErrConflict", err)
+		}
+	})
+
+	t.Run("empty ID", func(t *testing.T) {
+		r := newRepo(t)
+		if err := r.Save(ctx, &userrepo.User{FirstName: "Nobody"}); !errors.Is(err, userrepo.ErrInvalid) {
+			t.Fatalf("got %v, want userrepo.ErrInvalid", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		r := newRepo(t)
+		mustSave(t, r, ann())
+		u := mustGet(t, r, "ann")
+		u.Email = "ann@example.org"
+		mustSave(t, r, u)
+		if u.Version != 2 {
+			t.Fatalf("version after update = %d, want 2", u.Version)
+		}
+		if got := mustGet(t, r, "ann"); got.Email != "ann@example.org" || got.Version != 2 {
+			t.Fatalf("Get after update = %+v", got)
+		}
+	})
+
+	t.Run("stale update", func(t *testing.T) {
+		r := newRepo(t)
+		mustSave(t, r, ann())
+		first, second := mustGet(t, r, "ann"), mustGet(t, r, "ann")
+		first.LastName = "Park"
+		mustSave(t, r, first)
+		second.Email = "lee@example.com"
+		if err := r.Save(ctx, second); !errors.Is(err, userrepo.ErrConflict) {
+			t.Fatalf("stale save: got %v, want userrepo.ErrConflict", err)
+		}
+		if second.Version != 1 {
+			t.Fatalf("failed save changed version to %d", second.Version)
+		}
+		if got := mustGet(t, r, "ann"); got.LastName != "Park" || got.Email != "ann@example.com" {
+			t.Fatalf("stale save changed the user: %+v", got)
+		}
+	})
+
+	t.Run("update missing", func(t *testing.T) {
+		r := newRepo(t)
+		u := ann()
+		u.Version = 3
+		if err := r.Save(ctx, u); !errors.Is(err, userrepo.ErrNotFound) {
+			t.Fatalf("got %v, want userrepo.ErrNotFound", err)
+		}
+	})
+
+	t.Run("get returns a copy", func(t *testing.T) {
+		r := newRepo(t)
+		mustSave(t, r, ann())
+		mustGet(t, r, "ann").FirstName = "Changed"
+		if got := mustGet(t, r, "ann"); got.FirstName != "Ann" {
+			t.Fatalf("changing a returned user changed the store: %+v", got)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		r := newRepo(t)
+		if _, err := r.Get(ctx, "nobody"); !errors.Is(err, userrepo.ErrNotFound) {
+			t.Fatalf("got %v, want userrepo.ErrNotFound", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		r := newRepo(t)
+		list, err := r.List(ctx)
+		if err != nil || len(list) != 0 {
+			t.Fatalf("List on empty repository = %v, %v", list, err)
+		}
+		for _, id := range []string{"carol", "ann", "bob"} {
+			mustSave(t, r, &userrepo.User{ID: id, FirstName: id})
+		}
+		list, err = r.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, u := range list {
+			ids = append(ids, u.ID)
+		}
+		if fmt.Sprint(ids) != "[ann bob carol]" {
+			t.Fatalf("List IDs = %v, want [ann bob carol]", ids)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		r := newRepo(t)
+		u := ann()
+		mustSave(t, r, u)
+		if err := r.Delete(ctx, "ann", u.Version+1); !errors.Is(err, userrepo.ErrConflict) {
+			t.Fatalf("delete with wrong version: got %v, want userrepo.ErrConflict", err)
+		}
+		if err := r.Delete(ctx, "ann", u.Version); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := r.Get(ctx, "ann"); !errors.Is(err, userrepo.ErrNotFound) {
+			t.Fatalf("Get after delete: got %v, want userrepo.ErrNotFound", err)
+		}
+		if err := r.Delete(ctx, "ann", u.Version); !errors.Is(err, userrepo.ErrNotFound) {
+			t.Fatalf("second delete: got %v, want userrepo.ErrNotFound", err)
+		}
+		mustSave(t, r, ann()) // the ID can be reused
+	})
+
+	t.Run("concurrent updates", func(t *testing.T) {
+		r := newRepo(t)
+		mustSave(t, r, ann())
+		const writers = 8
+		var wg sync.WaitGroup
+		errs := make(chan error, writers)
+		for i := 0; i < writers; i++ {
+			u := mustGet(t, r, "ann")
+			u.Email = fmt.Sprintf("ann%d@example.com", i)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- r.Save(ctx, u)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		saved := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				saved++
+			case !errors.Is(err, userrepo.ErrConflict):
+				t.Errorf("concurrent save: %v", err)
+			}
+		}
+		if saved != 1 {
+			t.Fatalf("%d concurrent saves of version 1 succeeded, want 1", saved)
+		}
+		if got := mustGet(t, r, "ann"); got.Version != 2 {
+			t.Fatalf("version after concurrent saves = %d, want 2", got.Version)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		r := newRepo(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := r.Save(ctx, ann()); err == nil {
+			t.Fatal("Save with canceled context succeeded")
+		}
+	})
+}
+
+func mustSave(t *testing.T, r userrepo.UserRepository, u *userrepo.User) {
+	t.Helper()
+	if err := r.Save(context.Background(), u); err != nil {
+		t.Fatalf("Save(%s): %v", u.ID, err)
+	}
+}
+
+func mustGet(t *testing.T, r userrepo.UserRepository, id string) *userrepo.User {
+	t.Helper()
+	u, err := r.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	return u
+}
@@ -1,3 +1,7 @@
 module github.com/rnsasg/GO_Design/OOPS
 
 go 1.21.5
+
+require github.com/rnsasg/GO_Design v0.0.0
+
+replace github.com/rnsasg/GO_Design => ../
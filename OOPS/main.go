@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+
+	"github.com/rnsasg/GO_Design/OOPS/vehicle"
 )
 
 // Polymorphism
@@ -26,6 +28,10 @@ import (
 
 // // Encapsulation
 func main() {
-	v := vechile.NewVechile(2, vechile.RED)
+	v, err := vehicle.NewVechile(vehicle.WithWheels(2), vehicle.WithColor(vehicle.RED))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println(v.GetNoOfWheel())
 }
@@ -1,3 +1,8 @@
+//go:build ignore
+
+// This example stands alone: it redeclares names used by the other files
+// in the package, so it is excluded from the build.
+
 package vehicle
 
 import (
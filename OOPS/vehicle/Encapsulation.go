@@ -1,5 +1,11 @@
 package vehicle
 
+import (
+	"errors"
+
+	"github.com/rnsasg/GO_Design/Design_Pattern/Creational/option"
+)
+
 type Color int
 
 const (
@@ -14,8 +20,31 @@ type vechile struct {
 	color     Color
 }
 
-func NewVechile(noOfWheel int, color Color) *vechile {
-	return &vechile{noOfWheel: noOfWheel, color: color}
+var vechileSpec = option.Spec[vechile]{
+	Defaults: func(v *vechile) {
+		v.noOfWheel = 4
+		v.color = UNKNOWN
+	},
+	Validate: func(v *vechile) error {
+		if v.noOfWheel < 1 {
+			return errors.New("a vehicle needs at least one wheel")
+		}
+		return nil
+	},
+}
+
+// NewVechile returns a four-wheeled vehicle of unknown color, changed by
+// opts.
+func NewVechile(opts ...option.Option[vechile]) (*vechile, error) {
+	return vechileSpec.New(opts...)
+}
+
+func WithWheels(n int) option.Option[vechile] {
+	return option.New("WithWheels", func(v *vechile) { v.noOfWheel = n })
+}
+
+func WithColor(c Color) option.Option[vechile] {
+	return option.New("WithColor", func(v *vechile) { v.color = c })
 }
 
 func (v *vechile) GetNoOfWheel() int {
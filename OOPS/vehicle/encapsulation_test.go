@@ -0,0 +1,25 @@
+package vehicle
+
+import "testing"
+
+func TestNewVechile(t *testing.T) {
+	v, err := NewVechile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.GetNoOfWheel() != 4 || v.color != UNKNOWN {
+		t.Errorf("default vehicle = %+v, want 4 wheels of unknown color", *v)
+	}
+
+	v, err = NewVechile(WithWheels(2), WithColor(RED))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.GetNoOfWheel() != 2 || v.color != RED {
+		t.Errorf("vehicle = %+v, want 2 red wheels", *v)
+	}
+
+	if _, err := NewVechile(WithWheels(0)); err == nil {
+		t.Error("NewVechile accepted a vehicle without wheels")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/rnsasg/GO_Design
+
+go 1.21